@@ -29,7 +29,7 @@ func (s bsResults) PeekMinResult() Result {
 }
 
 func (bs *bsResults) PushResult(r Result) {
-	bs.Insert(r, bs.Search(r.Freq))
+	bs.Insert(r, bs.search(r))
 }
 
 // search finds the insertion index of the target
@@ -49,6 +49,23 @@ func (bs *bsResults) Search(freq uint64) int {
 	return i
 }
 
+// search finds the insertion index of r,
+// after the results with the same frequency and a smaller term
+func (bs *bsResults) search(r Result) int {
+	arr := *bs
+	i, j := 0, len(arr)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		// i ≤ h < j
+		if !ranksBelow(arr[h], r) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
 func (bs *bsResults) Insert(val Result, at int) {
 	*bs = append(*bs, val)
 	b := *bs
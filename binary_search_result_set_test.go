@@ -150,3 +150,19 @@ func TestPopResult(t *testing.T) {
 	assert.Equal(t, 5, bs.Len())
 	assert.Equal(t, prtrie.Result{Term: "5", Freq: 5}, popped)
 }
+
+func TestBSResultSetTiesByTerm(t *testing.T) {
+	b := prtrie.NewBSResultSet()
+	for _, term := range []string{"b", "d", "a", "c"} {
+		b.PushResult(prtrie.Result{Term: term, Freq: 1})
+	}
+	b.PushResult(prtrie.Result{Term: "e", Freq: 2})
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, b.PeekMinResult())
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, b.PopResult())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "e", Freq: 2},
+		{Term: "a", Freq: 1},
+		{Term: "b", Freq: 1},
+		{Term: "c", Freq: 1},
+	}, b.Results())
+}
@@ -0,0 +1,141 @@
+package pruningradixtrie
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NewFromResults builds a trie holding results in a single pass,
+// giving the same query results as adding each of them with AddTerm
+// but without re-sorting the ancestors of every term added.
+// results does not need to be sorted and is left untouched.
+func NewFromResults(results []Result, opts ...Option) *pruningRadixTrie {
+	p := NewPruningRadixTrie(opts...)
+	keyed := make([]keyedResult, 0, len(results))
+	for i, r := range results {
+		keyed = append(keyed, keyedResult{key: p.normalize(r.Term), index: i})
+	}
+	// equal keys keep their order so display forms with equal counts do too,
+	// which is much faster than a stable sort
+	sort.Slice(keyed, func(i, j int) bool {
+		if keyed[i].key != keyed[j].key {
+			return keyed[i].key < keyed[j].key
+		}
+		return keyed[i].index < keyed[j].index
+	})
+	b := p.newBuilder()
+	for _, r := range keyed {
+		// cannot fail, the keys are sorted
+		b.add(r.key, results[r.index])
+	}
+	b.finish()
+	return p
+}
+
+// BuildFromSorted builds a trie in a single pass from the results yielded by seq,
+// which must come in ascending byte order of their terms, or of their normalized
+// terms when a normalizer is used. Repeated terms are summed like with AddTerm.
+// Returns an error on the first result out of order.
+func BuildFromSorted(seq func(yield func(Result) bool), opts ...Option) (*pruningRadixTrie, error) {
+	p := NewPruningRadixTrie(opts...)
+	b := p.newBuilder()
+	var err error
+	seq(func(r Result) bool {
+		err = b.add(p.normalize(r.Term), r)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.finish()
+	return p, nil
+}
+
+type keyedResult struct {
+	key   string
+	index int
+}
+
+// builder appends terms in ascending order to an empty trie. It keeps the
+// path to the last term added open and finishes nodes, sorting their
+// children and computing their maxChildCount, once no more terms can be
+// added below them.
+type builder struct {
+	p    *pruningRadixTrie
+	prev string
+	// path from the root to the last term added, with the length
+	// of the key from the root down to each node
+	path  []*node
+	depth []int
+}
+
+func (p *pruningRadixTrie) newBuilder() *builder {
+	return &builder{
+		p:     p,
+		path:  []*node{p.trie},
+		depth: []int{0},
+	}
+}
+
+func (b *builder) add(key string, r Result) error {
+	if key == "" || r.Freq == 0 {
+		return nil
+	}
+	if key < b.prev {
+		return fmt.Errorf("pruningradixtrie: term %q is not sorted, it comes after %q", r.Term, b.prev)
+	}
+	if key != b.prev {
+		b.push(key)
+		b.p.termCount++
+	}
+	n := b.path[len(b.path)-1]
+	n.count += r.Freq
+	if b.p.cfg.normalizer != nil {
+		n.addForm(r.Term, r.Freq)
+	}
+	b.prev = key
+	return nil
+}
+
+// push closes the nodes below the common prefix of key and the previous key,
+// splitting the node that common prefix ends in, and opens a node for key.
+func (b *builder) push(key string) {
+	common := b.p.findCommon(b.prev, key)
+	for b.depth[len(b.depth)-1] > common {
+		n := b.pop()
+		parent, depth := b.path[len(b.path)-1], b.depth[len(b.depth)-1]
+		if depth >= common {
+			continue
+		}
+		// the common prefix ends inside the key of n
+		cut := common - depth
+		split := b.p.newNode(n.key[:cut], 0)
+		n.key = n.key[cut:]
+		split.children = append(split.children, n)
+		parent.children[len(parent.children)-1] = split
+		b.path = append(b.path, split)
+		b.depth = append(b.depth, common)
+	}
+	parent := b.path[len(b.path)-1]
+	n := b.p.newNode(key[common:], 0)
+	parent.children = append(parent.children, n)
+	b.path = append(b.path, n)
+	b.depth = append(b.depth, len(key))
+}
+
+// pop finishes the last node of the path and removes it.
+func (b *builder) pop() *node {
+	n := b.path[len(b.path)-1]
+	b.path = b.path[:len(b.path)-1]
+	b.depth = b.depth[:len(b.depth)-1]
+	recomputeMaxCount(n)
+	return n
+}
+
+// finish finishes every node left open, the root included.
+func (b *builder) finish() {
+	for len(b.path) > 0 {
+		b.pop()
+	}
+	b.p.scoreAll()
+}
@@ -0,0 +1,118 @@
+package pruningradixtrie_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// randomTerms returns terms with many shared prefixes and distinct frequencies,
+// so that the child order of a trie does not depend on how it was built.
+func randomTerms(n int, seed int64) []prtrie.Result {
+	rnd := rand.New(rand.NewSource(seed))
+	alphabet := []string{"a", "b", "ab", "é", "中", "丰", " "}
+	freqs := rnd.Perm(n * 10)
+	var results []prtrie.Result
+	for i := 0; i < n; i++ {
+		term := ""
+		for l := rnd.Intn(6) + 1; l > 0; l-- {
+			term += alphabet[rnd.Intn(len(alphabet))]
+		}
+		results = append(results, prtrie.Result{Term: term, Freq: uint64(freqs[i] + 1)})
+	}
+	return results
+}
+
+func sliceSeq(results []prtrie.Result) func(func(prtrie.Result) bool) {
+	return func(yield func(prtrie.Result) bool) {
+		for _, r := range results {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+func TestNewFromResultsMatchesAddTerm(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		results := randomTerms(200, seed)
+		incremental := prtrie.NewPruningRadixTrie()
+		for _, r := range results {
+			incremental.AddTerm(r.Term, r.Freq)
+		}
+		built := prtrie.NewFromResults(results)
+		require.Equal(t, incremental.GetTotalTermCount(), built.GetTotalTermCount(), "seed %d", seed)
+		for _, prefix := range []string{"", "a", "ab", "b", "é", "中", "\xe4\xb8", " "} {
+			assert.Equal(t, incremental.TopKForPrefix(prefix, 10), built.TopKForPrefix(prefix, 10), "seed %d prefix %q", seed, prefix)
+			assert.ElementsMatch(t, incremental.FindAllForPrefix(prefix), built.FindAllForPrefix(prefix), "seed %d prefix %q", seed, prefix)
+		}
+	}
+}
+
+func TestNewFromResultsStructure(t *testing.T) {
+	results := []prtrie.Result{
+		{Term: "testing", Freq: 2},
+		{Term: "food", Freq: 13},
+		{Term: "test", Freq: 1},
+		{Term: "tester", Freq: 5},
+		{Term: "test", Freq: 1},
+	}
+	p := prtrie.NewFromResults(results)
+	assert.Equal(t, "[0,13]\nfood[13,13]\ntest[2,5]\n    er[5,5]\n    ing[2,2]\n", p.String())
+	assert.Equal(t, uint64(4), p.GetTotalTermCount())
+	assert.Equal(t, "testing", results[0].Term, "expected results to be left untouched")
+
+	p.AddTerm("team", 20)
+	p.RemoveTerm("food")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "team", Freq: 20},
+		{Term: "tester", Freq: 5},
+	}, p.TopKForPrefix("te", 2), "expected built trie to be updatable")
+}
+
+func TestNewFromResultsNormalized(t *testing.T) {
+	p := prtrie.NewFromResults([]prtrie.Result{
+		{Term: "go", Freq: 1},
+		{Term: "Gopher", Freq: 3},
+		{Term: "Go", Freq: 2},
+		{Term: "", Freq: 2},
+		{Term: "zero", Freq: 0},
+	}, prtrie.WithNormalizer(prtrie.CaseFold))
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "Go", Freq: 3},
+		{Term: "Gopher", Freq: 3},
+	}, p.TopKForPrefix("GO", 2))
+}
+
+func TestBuildFromSorted(t *testing.T) {
+	results := randomTerms(500, 42)
+	sort.Slice(results, func(i, j int) bool { return results[i].Term < results[j].Term })
+	p, err := prtrie.BuildFromSorted(sliceSeq(results))
+	require.NoError(t, err)
+	assert.Equal(t, prtrie.NewFromResults(results).String(), p.String())
+
+	_, err = prtrie.BuildFromSorted(sliceSeq([]prtrie.Result{
+		{Term: "b", Freq: 1},
+		{Term: "a", Freq: 1},
+	}))
+	assert.EqualError(t, err, `pruningradixtrie: term "a" is not sorted, it comes after "b"`)
+}
+
+func TestBuildFromSortedByteKeys(t *testing.T) {
+	results := []prtrie.Result{{Term: "中文", Freq: 1}, {Term: "丰富", Freq: 2}}
+	sort.Slice(results, func(i, j int) bool { return results[i].Term < results[j].Term })
+	p, err := prtrie.BuildFromSorted(sliceSeq(results), prtrie.WithByteKeys())
+	require.NoError(t, err)
+	incremental := prtrie.NewPruningRadixTrie(prtrie.WithByteKeys())
+	for _, r := range results {
+		incremental.AddTerm(r.Term, r.Freq)
+	}
+	assert.Equal(t, incremental.String(), p.String())
+	assert.Equal(t, fmt.Sprint(incremental.FindAllForPrefix("")), fmt.Sprint(p.FindAllForPrefix("")))
+}
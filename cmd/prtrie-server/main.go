@@ -0,0 +1,44 @@
+// Command prtrie-server serves completions from a terms file over HTTP.
+//
+// Usage:
+//
+//	prtrie-server -terms terms.txt [-addr :8080] [-k 10] [-max-k 1000]
+//
+// Endpoints:
+//
+//	GET  /complete?prefix=mic&k=5  top k terms for prefix as a JSON array of {"term", "count"}
+//	POST /terms                    adds the counts of a JSON array of {"term", "count"}
+//	GET  /healthz                  reports the server is up
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	terms := flag.String("terms", "", "terms file to load, one term and its count separated by a tab per line, optionally .gz or .zip")
+	k := flag.Int("k", 10, "number of completions returned when k is not given")
+	maxK := flag.Int("max-k", 1000, "largest k accepted")
+	flag.Parse()
+
+	trie := prtrie.NewConcurrentPruningRadixTrie()
+	if *terms != "" {
+		results, err := prtrie.LoadTermsFile(*terms)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		trie = prtrie.NewConcurrentFrom(prtrie.NewFromResults(results))
+		log.Printf("loaded %d terms from %s", trie.GetTotalTermCount(), *terms)
+	}
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, newServer(trie, *k, *maxK)))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+)
+
+// maxBodyBytes limits the size of the POST /terms requests.
+const maxBodyBytes = 10 << 20
+
+// termCount is a term and the count to add to it in a POST /terms request,
+// or a completion and its count in the response to GET /complete.
+type termCount struct {
+	Term  string `json:"term"`
+	Count uint64 `json:"count"`
+}
+
+type server struct {
+	trie     prtrie.PruningRadixTrie
+	k        int
+	maxK     int
+	handlers *http.ServeMux
+}
+
+// newServer returns the handler of the endpoints for trie, which must be safe
+// for concurrent use. k is the number of completions returned by default
+// and maxK the largest number that can be asked for.
+func newServer(trie prtrie.PruningRadixTrie, k, maxK int) http.Handler {
+	s := &server{
+		trie:     trie,
+		k:        k,
+		maxK:     maxK,
+		handlers: http.NewServeMux(),
+	}
+	s.handlers.HandleFunc("GET /complete", s.complete)
+	s.handlers.HandleFunc("POST /terms", s.addTerms)
+	s.handlers.HandleFunc("GET /healthz", s.healthz)
+	return s.handlers
+}
+
+func (s *server) complete(w http.ResponseWriter, r *http.Request) {
+	k := s.k
+	if param := r.URL.Query().Get("k"); param != "" {
+		var err error
+		k, err = strconv.Atoi(param)
+		if err != nil || k < 1 || k > s.maxK {
+			http.Error(w, fmt.Sprintf("k must be a number from 1 to %d", s.maxK), http.StatusBadRequest)
+			return
+		}
+	}
+	results := s.trie.TopKForPrefix(r.URL.Query().Get("prefix"), k)
+	completions := make([]termCount, 0, len(results))
+	for _, r := range results {
+		completions = append(completions, termCount{Term: r.Term, Count: r.Freq})
+	}
+	writeJSON(w, completions)
+}
+
+func (s *server) addTerms(w http.ResponseWriter, r *http.Request) {
+	var terms []termCount
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&terms); err != nil {
+		http.Error(w, "expected a JSON array of {\"term\", \"count\"}: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, t := range terms {
+		if t.Term == "" || t.Count == 0 {
+			http.Error(w, fmt.Sprintf("term %q needs a term and a count above zero", t.Term), http.StatusBadRequest)
+			return
+		}
+	}
+	for _, t := range terms {
+		s.trie.AddTerm(t.Term, t.Count)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
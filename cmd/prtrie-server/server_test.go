@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	trie := prtrie.NewConcurrentPruningRadixTrie()
+	trie.AddTerm("microsoft", 500)
+	trie.AddTerm("micro", 20)
+	trie.AddTerm("microwave", 100)
+	ts := httptest.NewServer(newServer(trie, 2, 5))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func complete(t *testing.T, ts *httptest.Server, query url.Values) (int, []termCount) {
+	resp, err := http.Get(ts.URL + "/complete?" + query.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var results []termCount
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	return resp.StatusCode, results
+}
+
+func TestComplete(t *testing.T) {
+	ts := newTestServer(t)
+	status, results := complete(t, ts, url.Values{"prefix": {"mic"}, "k": {"3"}})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []termCount{
+		{Term: "microsoft", Count: 500},
+		{Term: "microwave", Count: 100},
+		{Term: "micro", Count: 20},
+	}, results)
+
+	// k defaults to the one of the server
+	_, results = complete(t, ts, url.Values{"prefix": {"mic"}})
+	assert.Len(t, results, 2)
+
+	_, results = complete(t, ts, url.Values{"prefix": {"xyz"}})
+	assert.Equal(t, []termCount{}, results)
+
+	for _, k := range []string{"0", "6", "ten"} {
+		status, _ = complete(t, ts, url.Values{"prefix": {"mic"}, "k": {k}})
+		assert.Equal(t, http.StatusBadRequest, status, "k %s", k)
+	}
+}
+
+func TestCompleteBody(t *testing.T) {
+	ts := newTestServer(t)
+	// completions use the field names POST /terms takes
+	for query, want := range map[string]string{
+		"prefix=micro&k=2": `[{"term":"microsoft","count":500},{"term":"microwave","count":100}]` + "\n",
+		"prefix=xyz":       "[]\n",
+	} {
+		resp, err := http.Get(ts.URL + "/complete?" + query)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, want, string(body), query)
+	}
+}
+
+func TestAddTerms(t *testing.T) {
+	ts := newTestServer(t)
+	resp, err := http.Post(ts.URL+"/terms", "application/json",
+		strings.NewReader(`[{"term": "micron", "count": 1000}, {"term": "micro", "count": 5}]`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, results := complete(t, ts, url.Values{"prefix": {"micro"}, "k": {"5"}})
+	assert.Equal(t, []termCount{
+		{Term: "micron", Count: 1000},
+		{Term: "microsoft", Count: 500},
+		{Term: "microwave", Count: 100},
+		{Term: "micro", Count: 25},
+	}, results)
+
+	for _, body := range []string{`{"term": "a", "count": 1}`, `[{"term": "", "count": 1}]`, `[{"term": "a"}]`, `[{"term": "a", "freq": 1}]`, `[`} {
+		resp, err := http.Post(ts.URL+"/terms", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+	}
+	// nothing was added by the rejected requests
+	_, results = complete(t, ts, url.Values{"prefix": {"a"}})
+	assert.Empty(t, results)
+
+	resp, err = http.Get(ts.URL + "/terms")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestHealthz(t *testing.T) {
+	ts := newTestServer(t)
+	resp, err := http.Get(ts.URL + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok\n", string(body))
+}
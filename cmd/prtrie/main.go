@@ -0,0 +1,186 @@
+// Command prtrie builds, queries and inspects pruning radix tries.
+//
+// Usage:
+//
+//	prtrie build -o trie.prt terms.txt   write the trie of a terms file to a binary file
+//	prtrie query [-k 10] trie.prt prefix print the top k terms for prefix
+//	prtrie stats trie.prt                print the statistics of the trie, see Stats
+//	prtrie dump trie.prt                 print the trie as a tree
+//
+// Terms files hold a term and its count separated by a tab per line, optionally
+// compressed as .gz or .zip. Every command reading a trie takes either a binary
+// file written by build or a terms file.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+)
+
+const usage = `usage:
+  prtrie build -o trie.prt terms.txt
+  prtrie query [-k 10] trie.prt prefix
+  prtrie stats trie.prt
+  prtrie dump trie.prt
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args, returning the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	commands := map[string]func([]string, io.Writer) error{
+		"build": build,
+		"query": query,
+		"stats": stats,
+		"dump":  dump,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "prtrie: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+	if err := command(args[1:], stdout); err != nil {
+		fmt.Fprintf(stderr, "prtrie %s: %s\n", args[0], err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+var errUsage = errors.New("invalid arguments")
+
+// parse parses the flags of a command expecting n positional arguments.
+func parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %s", errUsage, err)
+	}
+	if fs.NArg() != n {
+		return nil, fmt.Errorf("%w: expected %d arguments, got %d", errUsage, n, fs.NArg())
+	}
+	return fs.Args(), nil
+}
+
+func build(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	out := fs.String("o", "", "binary file to write")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("%w: -o is required", errUsage)
+	}
+	results, err := prtrie.LoadTermsFile(args[0])
+	if err != nil {
+		return err
+	}
+	trie := prtrie.NewFromResults(results)
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if _, err := trie.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %d terms to %s\n", trie.GetTotalTermCount(), *out)
+	return nil
+}
+
+func query(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	k := fs.Int("k", 10, "number of results")
+	args, err := parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	trie, err := load(args[0])
+	if err != nil {
+		return err
+	}
+	return prtrie.WriteTerms(stdout, trie.TopKForPrefix(args[1], *k))
+}
+
+func stats(args []string, stdout io.Writer) error {
+	args, err := parse(flag.NewFlagSet("stats", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	trie, err := load(args[0])
+	if err != nil {
+		return err
+	}
+	s := trie.Stats()
+	fmt.Fprintf(stdout, "terms\t%d\nnodes\t%d\nsplit nodes\t%d\nmax depth\t%d\navg depth\t%.2f\nkey bytes\t%d\nmemory bytes\t%d\n",
+		s.Terms, s.Nodes, s.SplitNodes, s.MaxDepth, s.AvgDepth, s.KeyBytes, s.MemoryBytes)
+	branching := make([]int, 0, len(s.Branching))
+	for children := range s.Branching {
+		branching = append(branching, children)
+	}
+	slices.Sort(branching)
+	for _, children := range branching {
+		fmt.Fprintf(stdout, "nodes with %d children\t%d\n", children, s.Branching[children])
+	}
+	return nil
+}
+
+func dump(args []string, stdout io.Writer) error {
+	args, err := parse(flag.NewFlagSet("dump", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	trie, err := load(args[0])
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, trie.String())
+	return err
+}
+
+// trie is what the commands need from a loaded trie.
+type trie interface {
+	prtrie.ReadOnlyTrie
+	Stats() prtrie.Stats
+	String() string
+}
+
+// load reads a binary file written by build, or else a terms file.
+func load(path string) (trie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t := prtrie.NewPruningRadixTrie()
+	_, err = t.ReadFrom(f)
+	if errors.Is(err, prtrie.ErrInvalidMagic) {
+		results, err := prtrie.LoadTermsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return prtrie.NewFromResults(results), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
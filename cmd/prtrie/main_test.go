@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runArgs runs the command line args, returning the exit code and outputs.
+func runArgs(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeTerms(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "terms.txt")
+	require.NoError(t, os.WriteFile(path, []byte("test\t10\ntesting\t20\nteam\t5\ntoast\t1\n"), 0o644))
+	return path
+}
+
+func TestBuildAndQuery(t *testing.T) {
+	terms := writeTerms(t)
+	out := filepath.Join(t.TempDir(), "trie.prt")
+	code, stdout, stderr := runArgs("build", "-o", out, terms)
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "wrote 4 terms to "+out+"\n", stdout)
+
+	// binary and terms files give the same results
+	for _, path := range []string{out, terms} {
+		code, stdout, stderr = runArgs("query", "-k", "2", path, "te")
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "testing\t20\ntest\t10\n", stdout)
+	}
+	code, stdout, _ = runArgs("query", out, "x")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+}
+
+func TestQueryTinyTermsFile(t *testing.T) {
+	// shorter than the binary header, still read as a terms file
+	path := filepath.Join(t.TempDir(), "tiny.txt")
+	require.NoError(t, os.WriteFile(path, []byte("a\t1\n"), 0o644))
+	code, stdout, stderr := runArgs("query", path, "a")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "a\t1\n", stdout)
+}
+
+func TestStats(t *testing.T) {
+	code, stdout, stderr := runArgs("stats", writeTerms(t))
+	assert.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "terms\t4\nnodes\t6\nsplit nodes\t2\nmax depth\t4\navg depth\t3.00\nkey bytes\t13\n")
+	assert.Contains(t, stdout, "memory bytes\t")
+	assert.True(t, strings.HasSuffix(stdout, "nodes with 0 children\t3\nnodes with 1 children\t2\nnodes with 2 children\t2\n"), stdout)
+}
+
+func TestDump(t *testing.T) {
+	code, stdout, stderr := runArgs("dump", writeTerms(t))
+	assert.Equal(t, 0, code, stderr)
+	assert.True(t, strings.HasPrefix(stdout, "[0,20]\n"), stdout)
+	assert.Contains(t, stdout, "ing[20,20]")
+}
+
+func TestErrors(t *testing.T) {
+	code, _, stderr := runArgs()
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage")
+
+	code, _, stderr = runArgs("frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+
+	code, _, _ = runArgs("build", writeTerms(t))
+	assert.Equal(t, 2, code, "missing -o")
+	code, _, _ = runArgs("query", writeTerms(t))
+	assert.Equal(t, 2, code, "missing prefix")
+	code, _, _ = runArgs("query", "-k", "ten", writeTerms(t), "te")
+	assert.Equal(t, 2, code, "invalid k")
+
+	code, _, stderr = runArgs("stats", filepath.Join(t.TempDir(), "missing.prt"))
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "prtrie stats:")
+
+	corrupt := filepath.Join(t.TempDir(), "corrupt.txt")
+	require.NoError(t, os.WriteFile(corrupt, []byte("test 10\n"), 0o644))
+	code, _, stderr = runArgs("dump", corrupt)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "line 1")
+}
@@ -0,0 +1,241 @@
+package pruningradixtrie
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+	"time"
+)
+
+// concurrentTrie guards a pruningRadixTrie with a read-write lock,
+// queries run concurrently with each other and are serialized with updates.
+type concurrentTrie struct {
+	mu sync.RWMutex
+	p  *pruningRadixTrie
+	// id orders the locks of tries merged into each other
+	id uint64
+}
+
+var _ PruningRadixTrie = &concurrentTrie{}
+var _ fmt.Stringer = &concurrentTrie{}
+
+// NewConcurrentPruningRadixTrie returns a trie that is safe for concurrent use,
+// any number of goroutines may query it while another one keeps adding terms.
+func NewConcurrentPruningRadixTrie(opts ...Option) *concurrentTrie {
+	return NewConcurrentFrom(NewPruningRadixTrie(opts...))
+}
+
+// NewConcurrentFrom makes a trie built by NewFromResults or ReadFrom safe for
+// concurrent use. p must only be used through the returned trie afterwards.
+func NewConcurrentFrom(p *pruningRadixTrie) *concurrentTrie {
+	return &concurrentTrie{
+		p:  p,
+		id: newGeneration(),
+	}
+}
+
+func (c *concurrentTrie) GetTotalTermCount() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.GetTotalTermCount()
+}
+
+// AddTerm implements PruningRadixTrie.
+func (c *concurrentTrie) AddTerm(term string, count uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.AddTerm(term, count)
+}
+
+// Merge adds every term of other to the trie, see pruningRadixTrie.Merge.
+// Both tries are locked for the merge, which also updates other, always in
+// the same order so that tries merged into each other do not deadlock.
+func (c *concurrentTrie) Merge(other *concurrentTrie) {
+	first, second := c, other
+	if other.id < c.id {
+		first, second = other, c
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	if second != first {
+		second.mu.Lock()
+		defer second.mu.Unlock()
+	}
+	c.p.Merge(other.p)
+}
+
+// AddTermAt adds term at time at, see pruningRadixTrie.AddTermAt.
+func (c *concurrentTrie) AddTermAt(term string, count uint64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.AddTermAt(term, count, at)
+}
+
+// Rescale moves the landmark of a decaying trie, see pruningRadixTrie.Rescale.
+func (c *concurrentTrie) Rescale(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.Rescale(at)
+}
+
+// DecayedScore converts the score of a result into a decayed count,
+// see pruningRadixTrie.DecayedScore.
+func (c *concurrentTrie) DecayedScore(score float64, at time.Time) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.DecayedScore(score, at)
+}
+
+// TopKForPrefix implements PruningRadixTrie.
+func (c *concurrentTrie) TopKForPrefix(prefix string, k int) []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.TopKForPrefix(prefix, k)
+}
+
+// TopKForPrefixAfter implements PruningRadixTrie.
+func (c *concurrentTrie) TopKForPrefixAfter(prefix string, k int, after Result) []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.TopKForPrefixAfter(prefix, k, after)
+}
+
+// TopKForFuzzyPrefix returns the top k terms within maxEdits of prefix,
+// see pruningRadixTrie.TopKForFuzzyPrefix.
+func (c *concurrentTrie) TopKForFuzzyPrefix(prefix string, k, maxEdits int) []FuzzyResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.TopKForFuzzyPrefix(prefix, k, maxEdits)
+}
+
+// FindAllForPrefix implements PruningRadixTrie.
+func (c *concurrentTrie) FindAllForPrefix(prefix string) []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.FindAllForPrefix(prefix)
+}
+
+// WalkPrefix implements PruningRadixTrie.
+// The walk goes over a snapshot of the trie without holding the lock,
+// so fn may query and update the trie, without seeing its own updates.
+func (c *concurrentTrie) WalkPrefix(prefix string, fn func(Result) bool) {
+	c.snapshot().WalkPrefix(prefix, fn)
+}
+
+// snapshot returns a snapshot of the trie to run the code of the caller on,
+// which would deadlock calling the trie if it ran under the lock.
+func (c *concurrentTrie) snapshot() *pruningRadixTrie {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.Snapshot().(*pruningRadixTrie)
+}
+
+// All returns an iterator over every term in lexicographic order,
+// see pruningRadixTrie.All and Range.
+func (c *concurrentTrie) All() iter.Seq2[string, uint64] {
+	return c.Range("", "")
+}
+
+// Range returns an iterator over the terms from from to to in lexicographic
+// order, see pruningRadixTrie.Range. Each loop goes over a snapshot of the
+// trie taken when it starts without holding the lock, so the loop body may
+// query and update the trie, without seeing its own updates.
+func (c *concurrentTrie) Range(from, to string) iter.Seq2[string, uint64] {
+	return func(yield func(string, uint64) bool) {
+		c.snapshot().Range(from, to)(yield)
+	}
+}
+
+// Successor returns the first term after term, see pruningRadixTrie.Successor.
+func (c *concurrentTrie) Successor(term string) (Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.Successor(term)
+}
+
+// Predecessor returns the last term before term, see pruningRadixTrie.Predecessor.
+func (c *concurrentTrie) Predecessor(term string) (Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.Predecessor(term)
+}
+
+// LongestPrefixOf returns the longest term s starts with,
+// see pruningRadixTrie.LongestPrefixOf.
+func (c *concurrentTrie) LongestPrefixOf(s string) (Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.LongestPrefixOf(s)
+}
+
+// AllPrefixesOf returns every term s starts with,
+// see pruningRadixTrie.AllPrefixesOf.
+func (c *concurrentTrie) AllPrefixesOf(s string) []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.AllPrefixesOf(s)
+}
+
+// Stats computes the statistics of the trie, see pruningRadixTrie.Stats.
+func (c *concurrentTrie) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.Stats()
+}
+
+// RemoveTerm implements PruningRadixTrie.
+func (c *concurrentTrie) RemoveTerm(term string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.RemoveTerm(term)
+}
+
+// DecrementTerm implements PruningRadixTrie.
+func (c *concurrentTrie) DecrementTerm(term string, by uint64) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.DecrementTerm(term, by)
+}
+
+// SetTerm implements PruningRadixTrie.
+func (c *concurrentTrie) SetTerm(term string, count uint64) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.SetTerm(term, count)
+}
+
+// Snapshot returns an immutable view of the trie that can be queried without
+// taking any lock while updates continue, see pruningRadixTrie.Snapshot.
+func (c *concurrentTrie) Snapshot() ReadOnlyTrie {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.Snapshot()
+}
+
+// Freeze converts the trie into a FrozenTrie, see pruningRadixTrie.Freeze.
+func (c *concurrentTrie) Freeze() (*FrozenTrie, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.Freeze()
+}
+
+// WriteTo serializes the trie, see pruningRadixTrie.WriteTo.
+func (c *concurrentTrie) WriteTo(w io.Writer) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.WriteTo(w)
+}
+
+// ReadFrom replaces the contents of the trie, see pruningRadixTrie.ReadFrom.
+func (c *concurrentTrie) ReadFrom(r io.Reader) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.p.ReadFrom(r)
+}
+
+func (c *concurrentTrie) String() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.p.String()
+}
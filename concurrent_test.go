@@ -0,0 +1,157 @@
+package pruningradixtrie_test
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentTrie(t *testing.T) {
+	p := prtrie.NewConcurrentPruningRadixTrie()
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 10},
+		{Term: "testing", Freq: 5},
+	}, p.TopKForPrefix("test", 2))
+	count, ok := p.DecrementTerm("tester", 6)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), count)
+	p.SetTerm("test", 1)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 1},
+		{Term: "testing", Freq: 5},
+		{Term: "tester", Freq: 4},
+	}, p.FindAllForPrefix("t"))
+	p.RemoveTerm("testing")
+	assert.Equal(t, "[0,4]\ntest[1,4]\n    er[4,4]\n", p.String())
+}
+
+func TestConcurrentTrieWalkPrefixCallsTrie(t *testing.T) {
+	p := prtrie.NewConcurrentPruningRadixTrie()
+	p.AddTerm("test", 2)
+	p.AddTerm("testing", 1)
+	var walked []string
+	p.WalkPrefix("test", func(r prtrie.Result) bool {
+		walked = append(walked, r.Term)
+		assert.NotEmpty(t, p.TopKForPrefix(r.Term, 1))
+		p.AddTerm(r.Term+"s", 1)
+		return true
+	})
+	assert.Equal(t, []string{"test", "testing"}, walked, "expected the walk not to see its own updates")
+	assert.Equal(t, uint64(4), p.GetTotalTermCount())
+}
+
+func TestConcurrentTrieRangeCallsTrie(t *testing.T) {
+	p := prtrie.NewConcurrentPruningRadixTrie()
+	p.AddTerm("b", 1)
+	p.AddTerm("d", 1)
+	var terms []string
+	for term := range p.All() {
+		terms = append(terms, term)
+		_, ok := p.LongestPrefixOf(term)
+		assert.True(t, ok)
+		p.AddTerm(term+"a", 1)
+	}
+	assert.Equal(t, []string{"b", "d"}, terms, "expected the loop not to see its own updates")
+	assert.Equal(t, uint64(4), p.GetTotalTermCount())
+}
+
+func TestNewConcurrentFrom(t *testing.T) {
+	p := prtrie.NewConcurrentFrom(prtrie.NewFromResults([]prtrie.Result{
+		{Term: "test", Freq: 80},
+		{Term: "tester", Freq: 10},
+	}))
+	p.AddTerm("testing", 20)
+	assert.Equal(t, uint64(3), p.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 80},
+		{Term: "testing", Freq: 20},
+	}, p.TopKForPrefix("te", 2))
+}
+
+// TestConcurrentTrieStress interleaves updates and queries,
+// run with -race to detect unsynchronized access.
+func TestConcurrentTrieStress(t *testing.T) {
+	const (
+		writers = 4
+		readers = 8
+		terms   = 500
+	)
+	p := prtrie.NewConcurrentPruningRadixTrie()
+	term := func(i int) string { return fmt.Sprintf("term %d", i) }
+
+	start := make(chan struct{})
+	var done atomic.Bool
+	var writing, reading sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		writing.Add(1)
+		go func(w int) {
+			defer writing.Done()
+			<-start
+			for i := 0; i < terms; i++ {
+				p.AddTerm(term(i), uint64(w+i%7+1))
+				if i%10 == 0 {
+					p.DecrementTerm(term(i/2), 1)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		reading.Add(1)
+		go func(r int) {
+			defer reading.Done()
+			<-start
+			for i := 0; !done.Load(); i++ {
+				results := p.TopKForPrefix(term(i%10), r+1)
+				assert.LessOrEqual(t, len(results), r+1)
+				assert.True(t, sort.SliceIsSorted(results, func(i, j int) bool {
+					return results[j].Freq < results[i].Freq
+				}), "expected results in descending order: %v", results)
+				p.WalkPrefix("term 1", func(prtrie.Result) bool { return i%2 == 0 })
+			}
+		}(r)
+	}
+	close(start)
+	writing.Wait()
+	done.Store(true)
+	reading.Wait()
+
+	assert.Equal(t, uint64(terms), p.GetTotalTermCount())
+	all := p.FindAllForPrefix("")
+	sort.Slice(all, func(i, j int) bool { return all[j].Freq < all[i].Freq })
+	top := p.TopKForPrefix("", 10)
+	for i := range top {
+		assert.Equal(t, all[i].Freq, top[i].Freq, "expected top k to agree with a full enumeration")
+	}
+}
+
+// TestConcurrentTrieMerge merges two tries into each other while both are
+// updated, run with -race to detect unsynchronized access to the source.
+func TestConcurrentTrieMerge(t *testing.T) {
+	a := prtrie.NewConcurrentPruningRadixTrie()
+	b := prtrie.NewConcurrentPruningRadixTrie()
+	a.AddTerm("test", 1)
+	b.AddTerm("team", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+		go func() { defer wg.Done(); a.Merge(b) }()
+		go func() { defer wg.Done(); b.Merge(a) }()
+		go func() { defer wg.Done(); a.AddTerm("test", 1) }()
+		go func() { defer wg.Done(); b.AddTerm(fmt.Sprintf("toast %d", i), 1) }()
+	}
+	wg.Wait()
+	assert.Positive(t, len(a.TopKForPrefix("toast", 10)))
+	assert.Equal(t, 2+10, int(b.GetTotalTermCount()), "expected b to hold test, team and every toast")
+
+	a.Merge(a)
+	assert.Equal(t, int(a.GetTotalTermCount()), len(a.FindAllForPrefix("")), "expected merging a trie into itself not to deadlock")
+}
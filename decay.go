@@ -0,0 +1,73 @@
+package pruningradixtrie
+
+import (
+	"math"
+	"time"
+)
+
+// maxDecayHalfLives is how many half-lives past the landmark AddTermAt goes
+// before rescaling the trie, keeping weights far from overflowing a float64.
+const maxDecayHalfLives = 256
+
+// AddTermAt adds count occurrences of term at time at to a trie created
+// WithDecay, like AddTerm does at the current time.
+//
+// Rather than storing when each term was last updated and decaying its count
+// before adding to it, the trie uses forward decay: a count added at time t
+// weighs count * 2^((t - landmark) / halfLife), landmark being a fixed time.
+// The decayed count of a term at any time is the sum of its weights times
+// 2^(-(now - landmark) / halfLife), the same factor for every term, so ranking
+// terms by their weights ranks them by decayed count whenever the query runs,
+// and the largest weight below a node prunes the search like maxChildCount.
+// Result.Score holds the weight, see DecayedScore.
+func (p *pruningRadixTrie) AddTermAt(term string, count uint64, at time.Time) {
+	if p.cfg.halfLife > 0 && !p.landmark.IsZero() && at.Sub(p.landmark)/p.cfg.halfLife > maxDecayHalfLives {
+		p.Rescale(at)
+	}
+	p.addTermAt(term, count, at)
+}
+
+// weight returns the weight of count occurrences added at time at.
+func (p *pruningRadixTrie) weight(count uint64, at time.Time) float64 {
+	if p.landmark.IsZero() {
+		p.landmark = at
+	}
+	return float64(count) * p.decay(at.Sub(p.landmark))
+}
+
+// decay returns the factor a count decays by over d.
+func (p *pruningRadixTrie) decay(d time.Duration) float64 {
+	return math.Exp2(float64(d) / float64(p.cfg.halfLife))
+}
+
+// DecayedScore converts the Score of a result from a trie created WithDecay
+// into the decayed count of the term at time at.
+func (p *pruningRadixTrie) DecayedScore(score float64, at time.Time) float64 {
+	if p.cfg.halfLife <= 0 {
+		return score
+	}
+	return score / p.decay(at.Sub(p.landmark))
+}
+
+// Rescale moves the landmark of a trie created WithDecay to at, dividing every
+// weight by the same factor and recomputing the largest weight below each node.
+// Weights grow exponentially with time, call Rescale periodically to keep them
+// small; AddTermAt does it by itself well before they overflow.
+func (p *pruningRadixTrie) Rescale(at time.Time) {
+	if p.cfg.halfLife <= 0 || p.landmark.IsZero() {
+		return
+	}
+	factor := p.decay(at.Sub(p.landmark))
+	p.trie = p.own(p.trie)
+	p.rescale(p.trie, factor)
+	p.landmark = at
+}
+
+// rescale divides the weights of the subtree of the owned node n by factor.
+func (p *pruningRadixTrie) rescale(n *node, factor float64) {
+	n.score /= factor
+	for i := range n.children {
+		p.rescale(p.ownChild(n, i), factor)
+	}
+	recomputeMaxCount(n)
+}
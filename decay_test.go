@@ -0,0 +1,74 @@
+package pruningradixtrie_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestDecayRanksRecentTermsHigher(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Hour))
+	p.AddTermAt("golang", 100, epoch)
+	p.AddTermAt("gopher", 30, epoch.Add(2*time.Hour))
+	// golang decayed to 25 by the time gopher was added
+	top := p.TopKForPrefix("go", 2)
+	assert.Equal(t, []string{"gopher", "golang"}, []string{top[0].Term, top[1].Term})
+	assert.Equal(t, uint64(100), top[1].Freq)
+	now := epoch.Add(3 * time.Hour)
+	assert.InDelta(t, 15, p.DecayedScore(top[0].Score, now), 1e-9)
+	assert.InDelta(t, 12.5, p.DecayedScore(top[1].Score, now), 1e-9)
+
+	// adding to a term adds to its decayed count
+	p.AddTermAt("golang", 10, now)
+	top = p.TopKForPrefix("go", 1)
+	assert.Equal(t, "golang", top[0].Term)
+	assert.Equal(t, uint64(110), top[0].Freq)
+	assert.InDelta(t, 22.5, p.DecayedScore(top[0].Score, now), 1e-9)
+}
+
+func TestDecayRescale(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Hour))
+	p.AddTermAt("test", 8, epoch)
+	p.AddTermAt("team", 4, epoch.Add(time.Hour))
+	later := epoch.Add(3 * time.Hour)
+	var decayed []float64
+	for _, r := range p.TopKForPrefix("te", 2) {
+		decayed = append(decayed, p.DecayedScore(r.Score, later))
+	}
+
+	p.Rescale(later)
+	after := p.TopKForPrefix("te", 2)
+	assert.Equal(t, []float64{1, 1}, []float64{after[0].Score, after[1].Score})
+	for i, r := range after {
+		assert.InDelta(t, decayed[i], p.DecayedScore(r.Score, later), 1e-9)
+	}
+}
+
+func TestDecayRescalesBeforeOverflow(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Second))
+	p.AddTermAt("old", 1000, epoch)
+	at := epoch
+	for i := 0; i < 20; i++ {
+		at = at.Add(100 * time.Second)
+		p.AddTermAt("new", 1, at)
+	}
+	top := p.TopKForPrefix("", 2)
+	assert.Equal(t, "new", top[0].Term)
+	assert.False(t, math.IsInf(top[0].Score, 0))
+	assert.InDelta(t, 1, p.DecayedScore(top[0].Score, at), 1e-9)
+}
+
+func TestDecayDecrementTerm(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Hour))
+	p.AddTermAt("test", 8, epoch)
+	p.AddTermAt("tent", 6, epoch)
+	p.DecrementTerm("test", 4)
+	top := p.TopKForPrefix("te", 2)
+	assert.Equal(t, "tent", top[0].Term)
+	assert.InDelta(t, 4, p.DecayedScore(top[1].Score, epoch), 1e-9)
+}
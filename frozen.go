@@ -0,0 +1,319 @@
+package pruningradixtrie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// The frozen layout is a single little endian byte slice made of
+//
+//	header  frozenMagic, version uint32, flags uint32, termCount uint64,
+//	        nodeCount uint32, stringBytes uint32
+//	nodes   nodeCount records of frozenNodeSize bytes in breadth first order,
+//	        so the children of a node are consecutive and follow it
+//	strings the node keys and display forms
+//
+// where each node record is
+//
+//	keyOffset uint32, keyLen uint32, count uint64, maxChildCount uint64,
+//	firstChild uint32, childCount uint32, displayOffset uint32, displayLen uint32
+const (
+	frozenMagic      = "PRFROZEN"
+	frozenVersion    = 1
+	frozenHeaderSize = 32
+	frozenNodeSize   = 40
+)
+
+// FrozenTrie is a read-only trie stored in a single pointer-free byte slice,
+// which makes it cheap for the garbage collector and lets it be used straight
+// from memory mapped files. It is safe for concurrent use.
+type FrozenTrie struct {
+	data      []byte
+	nodes     []byte
+	strings   []byte
+	nodeCount uint32
+	termCount uint64
+	cfg       config
+}
+
+var _ ReadOnlyTrie = &FrozenTrie{}
+
+// frozenNode is a decoded node record.
+type frozenNode struct {
+	keyOffset, keyLen         uint32
+	count, maxChildCount      uint64
+	firstChild, childCount    uint32
+	displayOffset, displayLen uint32
+}
+
+// Freeze converts the trie into a FrozenTrie, which does not change
+// when the trie is updated afterwards.
+// Fails if the trie has more than 2^32 nodes or 4GiB of keys,
+// or if it has a Scorer or decay since the frozen layout does not hold scores.
+func (p *pruningRadixTrie) Freeze() (*FrozenTrie, error) {
+	if p.scored() {
+		return nil, errors.New("pruningradixtrie: a trie with a scorer or decay cannot be frozen")
+	}
+	var nodes []*node
+	var stringBytes uint64
+	for queue := []*node{p.trie}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		nodes = append(nodes, n)
+		stringBytes += uint64(len(n.key))
+		if len(n.forms) > 0 {
+			stringBytes += uint64(len(n.forms[0].term))
+		}
+		queue = append(queue, n.children...)
+	}
+	if len(nodes) > math.MaxUint32 || stringBytes > math.MaxUint32 {
+		return nil, fmt.Errorf("pruningradixtrie: %d nodes and %d bytes of keys are too large to freeze", len(nodes), stringBytes)
+	}
+
+	var flags uint32
+	if p.cfg.normalizer != nil {
+		flags |= flagForms
+	}
+	size := frozenHeaderSize + len(nodes)*frozenNodeSize + int(stringBytes)
+	b := make([]byte, 0, size)
+	b = append(b, frozenMagic...)
+	b = binary.LittleEndian.AppendUint32(b, frozenVersion)
+	b = binary.LittleEndian.AppendUint32(b, flags)
+	b = binary.LittleEndian.AppendUint64(b, p.termCount)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(nodes)))
+	b = binary.LittleEndian.AppendUint32(b, uint32(stringBytes))
+
+	strings := make([]byte, 0, stringBytes)
+	next := uint32(1)
+	for _, n := range nodes {
+		r := frozenNode{
+			keyOffset:     uint32(len(strings)),
+			keyLen:        uint32(len(n.key)),
+			count:         n.count,
+			maxChildCount: n.maxChildCount,
+			firstChild:    next,
+			childCount:    uint32(len(n.children)),
+		}
+		strings = append(strings, n.key...)
+		if len(n.forms) > 0 {
+			r.displayOffset = uint32(len(strings))
+			r.displayLen = uint32(len(n.forms[0].term))
+			strings = append(strings, n.forms[0].term...)
+		}
+		next += r.childCount
+		b = r.append(b)
+	}
+	b = append(b, strings...)
+	return OpenFrozen(b, func(c *config) { *c = p.cfg })
+}
+
+// OpenFrozen opens a FrozenTrie from the bytes returned by FrozenTrie.Bytes,
+// typically memory mapped from a file. Only the header is checked so opening
+// takes constant time, node records pointing outside of data are treated
+// as empty. data must not be modified while the trie is in use.
+// A trie frozen with a normalizer must be opened with one and vice versa.
+func OpenFrozen(data []byte, opts ...Option) (*FrozenTrie, error) {
+	if len(data) < frozenHeaderSize || string(data[:len(frozenMagic)]) != frozenMagic {
+		return nil, ErrInvalidMagic
+	}
+	header := data[len(frozenMagic):]
+	if version := binary.LittleEndian.Uint32(header); version != frozenVersion {
+		return nil, fmt.Errorf("%w %d, expected %d", ErrUnsupportedVersion, version, frozenVersion)
+	}
+	flags := binary.LittleEndian.Uint32(header[4:])
+	if flags&^flagForms != 0 {
+		return nil, fmt.Errorf("%w: unknown flags %032b", ErrCorrupt, flags)
+	}
+	f := &FrozenTrie{
+		data:      data,
+		termCount: binary.LittleEndian.Uint64(header[8:]),
+		nodeCount: binary.LittleEndian.Uint32(header[16:]),
+		cfg:       newConfig(opts),
+	}
+	stringBytes := uint64(binary.LittleEndian.Uint32(header[20:]))
+	nodeBytes := uint64(f.nodeCount) * frozenNodeSize
+	if f.nodeCount == 0 || frozenHeaderSize+nodeBytes+stringBytes != uint64(len(data)) {
+		return nil, fmt.Errorf("%w: %d nodes and %d bytes of keys do not fit in %d bytes", ErrCorrupt, f.nodeCount, stringBytes, len(data))
+	}
+	if forms := flags&flagForms != 0; forms && f.cfg.normalizer == nil {
+		return nil, errors.New("pruningradixtrie: trie was frozen with a normalizer, open it with one")
+	} else if !forms && f.cfg.normalizer != nil {
+		return nil, errors.New("pruningradixtrie: trie was frozen without a normalizer, open it without one")
+	}
+	f.nodes = data[frozenHeaderSize : frozenHeaderSize+nodeBytes]
+	f.strings = data[frozenHeaderSize+nodeBytes:]
+	return f, nil
+}
+
+// Bytes returns the frozen trie, to be stored and later opened with OpenFrozen.
+func (f *FrozenTrie) Bytes() []byte {
+	return f.data
+}
+
+// GetTotalTermCount implements ReadOnlyTrie.
+func (f *FrozenTrie) GetTotalTermCount() uint64 {
+	return f.termCount
+}
+
+func (r frozenNode) append(b []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, r.keyOffset)
+	b = binary.LittleEndian.AppendUint32(b, r.keyLen)
+	b = binary.LittleEndian.AppendUint64(b, r.count)
+	b = binary.LittleEndian.AppendUint64(b, r.maxChildCount)
+	b = binary.LittleEndian.AppendUint32(b, r.firstChild)
+	b = binary.LittleEndian.AppendUint32(b, r.childCount)
+	b = binary.LittleEndian.AppendUint32(b, r.displayOffset)
+	return binary.LittleEndian.AppendUint32(b, r.displayLen)
+}
+
+// node decodes the i-th node record. Children are only reported if they
+// come after the node and fit in the trie, which guarantees walks terminate
+// even on corrupted data.
+func (f *FrozenTrie) node(i uint32) frozenNode {
+	b := f.nodes[uint64(i)*frozenNodeSize:]
+	r := frozenNode{
+		keyOffset:     binary.LittleEndian.Uint32(b),
+		keyLen:        binary.LittleEndian.Uint32(b[4:]),
+		count:         binary.LittleEndian.Uint64(b[8:]),
+		maxChildCount: binary.LittleEndian.Uint64(b[16:]),
+		firstChild:    binary.LittleEndian.Uint32(b[24:]),
+		childCount:    binary.LittleEndian.Uint32(b[28:]),
+		displayOffset: binary.LittleEndian.Uint32(b[32:]),
+		displayLen:    binary.LittleEndian.Uint32(b[36:]),
+	}
+	if r.firstChild <= i || uint64(r.firstChild)+uint64(r.childCount) > uint64(f.nodeCount) {
+		r.childCount = 0
+	}
+	return r
+}
+
+// str returns the bytes of the strings section at offset, or nil if out of range.
+func (f *FrozenTrie) str(offset, l uint32) []byte {
+	if uint64(offset)+uint64(l) > uint64(len(f.strings)) {
+		return nil
+	}
+	return f.strings[offset : offset+l]
+}
+
+// term returns the display form of the term held by r, key being its full key.
+func (f *FrozenTrie) term(r frozenNode, key []byte) string {
+	if r.displayLen > 0 {
+		return string(f.str(r.displayOffset, r.displayLen))
+	}
+	return string(key)
+}
+
+func (f *FrozenTrie) normalize(prefix string) []byte {
+	if f.cfg.normalizer != nil {
+		prefix = f.cfg.normalizer(prefix)
+	}
+	return []byte(prefix)
+}
+
+// TopKForPrefix implements ReadOnlyTrie.
+func (f *FrozenTrie) TopKForPrefix(prefix string, k int) []Result {
+	return f.TopKForPrefixAfter(prefix, k, Result{})
+}
+
+// TopKForPrefixAfter implements ReadOnlyTrie.
+func (f *FrozenTrie) TopKForPrefixAfter(prefix string, k int, after Result) []Result {
+	if k <= 0 {
+		return nil
+	}
+	results := newTopKResults(prefix, k, after)
+	ordered := f.cfg.normalizer == nil
+	f.topKForPrefix(f.normalize(prefix), nil, f.node(0), k, results, ordered)
+	return results.Results()
+}
+
+// topKForPrefix mirrors topKForPrefix on the frozen layout.
+func (f *FrozenTrie) topKForPrefix(prefix, path []byte, cur frozenNode, k int, results ResultSet, ordered bool) {
+	if !canRankIn(results, k, 0, cur.maxChildCount, path, nil, ordered) {
+		return
+	}
+
+	noPrefix := len(prefix) == 0
+	siblings := noPrefix || !utf8.FullRune(prefix)
+	for i := cur.firstChild; i < cur.firstChild+cur.childCount; i++ {
+		child := f.node(i)
+		key := f.str(child.keyOffset, child.keyLen)
+		if !canRankIn(results, k, 0, child.maxChildCount, path, key, ordered) {
+			if child.maxChildCount == results.PeekMinResult().Freq {
+				continue
+			}
+			break
+		}
+		term := append(path, key...)
+		if noPrefix || bytes.HasPrefix(key, prefix) {
+			if child.count > 0 {
+				results.PushResult(Result{Term: f.term(child, term), Freq: child.count})
+				if results.Len() > k {
+					results.PopResult()
+				}
+			}
+			if child.childCount > 0 {
+				f.topKForPrefix(nil, term, child, k, results, ordered)
+			}
+			if !siblings {
+				break
+			}
+		} else if bytes.HasPrefix(prefix, key) {
+			if child.childCount > 0 {
+				f.topKForPrefix(prefix[len(key):], term, child, k, results, ordered)
+			}
+		}
+	}
+}
+
+// FindAllForPrefix implements ReadOnlyTrie.
+func (f *FrozenTrie) FindAllForPrefix(prefix string) []Result {
+	var results []Result
+	f.WalkPrefix(prefix, func(r Result) bool {
+		results = append(results, r)
+		return true
+	})
+	return results
+}
+
+// WalkPrefix implements ReadOnlyTrie, visiting terms in the same order
+// as the trie it was frozen from.
+func (f *FrozenTrie) WalkPrefix(prefix string, fn func(Result) bool) {
+	f.walkPrefix(f.normalize(prefix), nil, f.node(0), fn)
+}
+
+// walkPrefix mirrors walkPrefix on the frozen layout.
+func (f *FrozenTrie) walkPrefix(prefix, path []byte, cur frozenNode, fn func(Result) bool) bool {
+	partial := len(prefix) > 0 && !utf8.FullRune(prefix)
+	for i := cur.firstChild; i < cur.firstChild+cur.childCount; i++ {
+		child := f.node(i)
+		key := f.str(child.keyOffset, child.keyLen)
+		if len(prefix) == 0 || bytes.HasPrefix(key, prefix) {
+			if !f.walk(path, child, fn) {
+				return false
+			}
+			if len(prefix) > 0 && !partial {
+				return true
+			}
+		} else if bytes.HasPrefix(prefix, key) {
+			return f.walkPrefix(prefix[len(key):], append(path, key...), child, fn)
+		}
+	}
+	return true
+}
+
+// walk mirrors walk on the frozen layout.
+func (f *FrozenTrie) walk(path []byte, n frozenNode, fn func(Result) bool) bool {
+	term := append(path, f.str(n.keyOffset, n.keyLen)...)
+	if n.count > 0 && !fn(Result{Term: f.term(n, term), Freq: n.count}) {
+		return false
+	}
+	for i := n.firstChild; i < n.firstChild+n.childCount; i++ {
+		if !f.walk(term, f.node(i), fn) {
+			return false
+		}
+	}
+	return true
+}
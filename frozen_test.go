@@ -0,0 +1,88 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeMatchesTrie(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	for _, r := range unicodeTerms {
+		p.AddTerm(r.Term, r.Freq)
+	}
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	p.AddTerm("food", 13)
+	f, err := p.Freeze()
+	require.NoError(t, err)
+	assert.Equal(t, p.GetTotalTermCount(), f.GetTotalTermCount())
+
+	prefixes := []string{"", "t", "te", "test", "teste", "tests", "x", "中", "\xe4\xb8", "👨‍👩‍", "🇫", "café"}
+	for _, prefix := range prefixes {
+		for _, k := range []int{0, 1, 2, 3, 100} {
+			assert.Equal(t, p.TopKForPrefix(prefix, k), f.TopKForPrefix(prefix, k), "expected same top %d for %q", k, prefix)
+		}
+		assert.Equal(t, p.FindAllForPrefix(prefix), f.FindAllForPrefix(prefix), "expected same terms for %q", prefix)
+	}
+
+	p.AddTerm("testing", 100)
+	assert.Equal(t, []prtrie.Result{{Term: "test", Freq: 80}}, f.TopKForPrefix("te", 1), "expected frozen trie not to change")
+}
+
+func TestOpenFrozen(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Go", 2)
+	p.AddTerm("go", 1)
+	p.AddTerm("Gopher", 3)
+	frozen, err := p.Freeze()
+	require.NoError(t, err)
+	data := append([]byte(nil), frozen.Bytes()...)
+
+	_, err = prtrie.OpenFrozen(data)
+	assert.Error(t, err, "expected normalized trie to require a normalizer")
+	f, err := prtrie.OpenFrozen(data, prtrie.WithNormalizer(prtrie.CaseFold))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), f.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "Go", Freq: 3},
+		{Term: "Gopher", Freq: 3},
+	}, f.TopKForPrefix("GO", 2))
+	assert.Equal(t, []prtrie.Result{{Term: "Gopher", Freq: 3}}, f.FindAllForPrefix("gop"))
+}
+
+func TestOpenFrozenRejectsInvalidData(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	frozen, err := p.Freeze()
+	require.NoError(t, err)
+	data := frozen.Bytes()
+
+	_, err = prtrie.OpenFrozen([]byte("PRFROZEN"))
+	assert.ErrorIs(t, err, prtrie.ErrInvalidMagic)
+	_, err = prtrie.OpenFrozen(data[:len(data)-1])
+	assert.ErrorIs(t, err, prtrie.ErrCorrupt)
+
+	version := append([]byte(nil), data...)
+	version[8] = 2
+	_, err = prtrie.OpenFrozen(version)
+	assert.ErrorIs(t, err, prtrie.ErrUnsupportedVersion)
+
+	// corrupted node records must not make queries panic or loop
+	for i := 32; i < len(data); i++ {
+		corrupted := append([]byte(nil), data...)
+		corrupted[i] ^= 0xff
+		f, err := prtrie.OpenFrozen(corrupted)
+		require.NoError(t, err)
+		assert.NotPanics(t, func() {
+			f.TopKForPrefix("", 10)
+			f.TopKForPrefix("te", 10)
+			f.FindAllForPrefix("")
+		}, "expected data corrupted at %d to be queried safely", i)
+	}
+}
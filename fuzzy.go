@@ -0,0 +1,139 @@
+package pruningradixtrie
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// FuzzyResult is a term found by TopKForFuzzyPrefix, Distance being the
+// edit distance between the query and the closest prefix of the term.
+type FuzzyResult struct {
+	Result
+	Distance int
+}
+
+func (r FuzzyResult) String() string {
+	return fmt.Sprintf("%s~%d", r.Result, r.Distance)
+}
+
+// TopKForFuzzyPrefix returns the top k terms starting with a prefix that is
+// within maxEdits Levenshtein edits (rune insertions, deletions and substitutions)
+// of prefix. Results are ranked by fewest edits first and then by frequency
+// (or score with a Scorer),
+// so exact completions always come before corrected ones.
+//
+// The trie is walked with one row of the edit distance matrix per rune of the
+// node keys, branches are abandoned as soon as every entry of the row exceeds
+// maxEdits, and maxChildCount prunes branches that cannot make it into the top k.
+func (p *pruningRadixTrie) TopKForFuzzyPrefix(prefix string, k, maxEdits int) []FuzzyResult {
+	if k <= 0 || maxEdits < 0 {
+		return nil
+	}
+	f := &fuzzySearch{
+		query:    []rune(p.normalize(prefix)),
+		k:        k,
+		maxEdits: maxEdits,
+	}
+	row := make([]int, len(f.query)+1)
+	for j := range row {
+		row[j] = j
+	}
+	f.search(p.trie, "", "", row, row[len(row)-1])
+	return f.results
+}
+
+// fuzzySearch collects the results of TopKForFuzzyPrefix,
+// results are kept sorted from best to worst.
+type fuzzySearch struct {
+	query    []rune
+	k        int
+	maxEdits int
+	results  []FuzzyResult
+}
+
+// search visits the children of cur. row is the edit distance row for path
+// and best the fewest edits between the query and any prefix of path.
+// pending holds the bytes of a rune split over several nodes by byte keys.
+func (f *fuzzySearch) search(cur *node, path, pending string, row []int, best int) {
+	for _, child := range cur.children {
+		if !f.canBeat(min(best, minRow(row)), child.maxScore, child.maxChildCount) {
+			// siblings come in descending order of maxScore and maxChildCount
+			break
+		}
+		r, b, s := row, best, pending+child.key
+		for s != "" && utf8.FullRuneInString(s) {
+			c, size := utf8.DecodeRuneInString(s)
+			s = s[size:]
+			r = f.next(r, c)
+			b = min(b, r[len(r)-1])
+			if b > f.maxEdits && minRow(r) > f.maxEdits {
+				break
+			}
+		}
+		if b > f.maxEdits && minRow(r) > f.maxEdits {
+			continue
+		}
+		term := path + child.key
+		if child.count > 0 && b <= f.maxEdits {
+			f.push(FuzzyResult{Result: Result{Term: child.term(term), Freq: child.count, Score: child.score}, Distance: b})
+		}
+		if len(child.children) > 0 {
+			f.search(child, term, s, r, b)
+		}
+	}
+}
+
+// next computes the edit distance row after appending c to the term.
+func (f *fuzzySearch) next(row []int, c rune) []int {
+	next := make([]int, len(row))
+	next[0] = row[0] + 1
+	for j := 1; j < len(row); j++ {
+		cost := 1
+		if f.query[j-1] == c {
+			cost = 0
+		}
+		next[j] = min(row[j]+1, next[j-1]+1, row[j-1]+cost)
+	}
+	return next
+}
+
+func minRow(row []int) int {
+	m := row[0]
+	for _, v := range row[1:] {
+		m = min(m, v)
+	}
+	return m
+}
+
+// less reports whether a ranks below b.
+func (f *fuzzySearch) less(a, b FuzzyResult) bool {
+	if a.Distance != b.Distance {
+		return a.Distance > b.Distance
+	}
+	return ranksBelow(a.Result, b.Result)
+}
+
+// canBeat reports whether a term with the given distance, score and frequency
+// would make it into the results.
+func (f *fuzzySearch) canBeat(distance int, score float64, freq uint64) bool {
+	if len(f.results) < f.k {
+		return true
+	}
+	return f.less(f.results[len(f.results)-1], FuzzyResult{Result: Result{Freq: freq, Score: score}, Distance: distance})
+}
+
+func (f *fuzzySearch) push(r FuzzyResult) {
+	if !f.canBeat(r.Distance, r.Score, r.Freq) {
+		return
+	}
+	i := sort.Search(len(f.results), func(i int) bool {
+		return f.less(f.results[i], r)
+	})
+	f.results = append(f.results, FuzzyResult{})
+	copy(f.results[i+1:], f.results[i:])
+	f.results[i] = r
+	if len(f.results) > f.k {
+		f.results = f.results[:f.k]
+	}
+}
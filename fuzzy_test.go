@@ -0,0 +1,101 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func fuzzy(term string, freq uint64, distance int) prtrie.FuzzyResult {
+	return prtrie.FuzzyResult{Result: prtrie.Result{Term: term, Freq: freq}, Distance: distance}
+}
+
+func TestTopKForFuzzyPrefix(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("microsoft", 500)
+	p.AddTerm("microsoft office", 300)
+	p.AddTerm("micro", 20)
+	p.AddTerm("mirror", 40)
+	p.AddTerm("nicotine", 10)
+	p.AddTerm("macro", 30)
+
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 0),
+		fuzzy("microsoft office", 300, 0),
+		fuzzy("micro", 20, 0),
+	}, p.TopKForFuzzyPrefix("micro", 3, 0), "expected no edits to behave like TopKForPrefix")
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 1),
+		fuzzy("microsoft office", 300, 1),
+		fuzzy("macro", 30, 1),
+		fuzzy("micro", 20, 1),
+	}, p.TopKForFuzzyPrefix("mucro", 10, 1), "expected substitutions to be corrected")
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 1),
+		fuzzy("microsoft office", 300, 1),
+		fuzzy("micro", 20, 1),
+		fuzzy("nicotine", 10, 1),
+	}, p.TopKForFuzzyPrefix("nicro", 10, 1))
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 2),
+		fuzzy("microsoft office", 300, 2),
+	}, p.TopKForFuzzyPrefix("mircosoft", 10, 2), "expected transpositions to cost two edits")
+	assert.Empty(t, p.TopKForFuzzyPrefix("mircosoft", 10, 1))
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 1),
+		fuzzy("microsoft office", 300, 1),
+	}, p.TopKForFuzzyPrefix("microsooft", 10, 1), "expected insertions to be corrected")
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("mirror", 40, 0),
+		fuzzy("microsoft", 500, 1),
+		fuzzy("microsoft office", 300, 1),
+	}, p.TopKForFuzzyPrefix("mirr", 3, 1), "expected fewer edits to rank first")
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("microsoft", 500, 0),
+		fuzzy("microsoft office", 300, 0),
+	}, p.TopKForFuzzyPrefix("", 2, 1), "expected empty prefix to match everything")
+	assert.Empty(t, p.TopKForFuzzyPrefix("micro", 0, 1))
+	assert.Empty(t, p.TopKForFuzzyPrefix("micro", 1, -1))
+}
+
+func TestTopKForFuzzyPrefixRunes(t *testing.T) {
+	for _, opts := range [][]prtrie.Option{nil, {prtrie.WithByteKeys()}} {
+		p := prtrie.NewPruningRadixTrie(opts...)
+		p.AddTerm("中文", 50)
+		p.AddTerm("丰富", 30)
+		p.AddTerm("café au lait", 7)
+		assert.Equal(t, []prtrie.FuzzyResult{
+			fuzzy("中文", 50, 1),
+		}, p.TopKForFuzzyPrefix("丸文", 10, 1), "expected multi-byte runes to count as one edit")
+		assert.Equal(t, []prtrie.FuzzyResult{
+			fuzzy("中文", 50, 1),
+			fuzzy("丰富", 30, 2),
+		}, p.TopKForFuzzyPrefix("丸文", 2, 2))
+		assert.Equal(t, []prtrie.FuzzyResult{
+			fuzzy("café au lait", 7, 1),
+		}, p.TopKForFuzzyPrefix("cafe au", 10, 1))
+	}
+}
+
+func TestTopKForFuzzyPrefixNormalized(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Microsoft", 500)
+	assert.Equal(t, []prtrie.FuzzyResult{
+		fuzzy("Microsoft", 500, 1),
+	}, p.TopKForFuzzyPrefix("MYCRO", 10, 1))
+	assert.Equal(t, "Microsoft:500~1", p.TopKForFuzzyPrefix("MYCRO", 10, 1)[0].String())
+}
+
+// TestTopKForFuzzyPrefixPruning compares the pruned search with a
+// ranking of every term within the distance.
+func TestTopKForFuzzyPrefixPruning(t *testing.T) {
+	results := randomTerms(300, 7)
+	p := prtrie.NewFromResults(results)
+	for _, prefix := range []string{"a", "ab", "ba", "中a", "éé", "b b"} {
+		all := p.TopKForFuzzyPrefix(prefix, len(results), 1)
+		for _, k := range []int{1, 3, 10} {
+			assert.Equal(t, all[:min(k, len(all))], p.TopKForFuzzyPrefix(prefix, k, 1), "prefix %q k %d", prefix, k)
+		}
+	}
+}
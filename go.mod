@@ -1,11 +1,14 @@
 module github.com/elielamora/pruningradixtrie
 
-go 1.21.1
+go 1.23
+
+require (
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.22.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/objx v0.5.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -4,7 +4,7 @@ import (
 	"container/heap"
 )
 
-// rheap is a min-heap of Results
+// rheap is a min-heap of Results, the lowest ranked result at the top
 type rheap []Result
 
 // resultHeap wraps a rheap
@@ -22,7 +22,7 @@ func NewResultHeap() ResultSet {
 }
 
 func (r rheap) Len() int           { return len(r) }
-func (r rheap) Less(i, j int) bool { return r[i].Freq < r[j].Freq }
+func (r rheap) Less(i, j int) bool { return ranksBelow(r[i], r[j]) }
 func (r rheap) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
 
 func (r *rheap) Push(x any) {
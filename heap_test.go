@@ -38,3 +38,19 @@ func TestHeapAdd(t *testing.T) {
 		{Term: "bar", Freq: 1},
 	}, h.Results())
 }
+
+func TestHeapTiesByTerm(t *testing.T) {
+	h := prtrie.NewResultHeap()
+	for _, term := range []string{"b", "d", "a", "c"} {
+		h.PushResult(prtrie.Result{Term: term, Freq: 1})
+	}
+	h.PushResult(prtrie.Result{Term: "e", Freq: 2})
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, h.PeekMinResult())
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, h.PopResult())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "e", Freq: 2},
+		{Term: "a", Freq: 1},
+		{Term: "b", Freq: 1},
+		{Term: "c", Freq: 1},
+	}, h.Results())
+}
@@ -0,0 +1,100 @@
+package pruningradixtrie
+
+// Merge adds every term of other to the trie, summing the counts of the
+// terms both hold. Both radix trees are walked side by side, splitting keys
+// where they diverge, and the subtrees only other holds are shared with it
+// rather than copied: like after a Snapshot, both tries copy the shared nodes
+// before changing them, so other can still be used and updated afterwards.
+// other must have been created with the same options as the trie.
+func (p *pruningRadixTrie) Merge(other *pruningRadixTrie) {
+	src := other.trie
+	other.gen = newGeneration()
+	if p.cfg.halfLife > 0 && !other.landmark.IsZero() {
+		// weights can only be added up relative to the same landmark
+		if p.landmark.IsZero() {
+			p.landmark = other.landmark
+		} else {
+			p.Rescale(other.landmark)
+		}
+	}
+	p.trie = p.own(p.trie)
+	p.termCount += other.termCount
+	for _, child := range src.children {
+		p.mergeChild(p.trie, "", child)
+	}
+	recomputeMaxCount(p.trie)
+}
+
+// mergeChild merges the subtree of b into the children of the owned node
+// parent, path being the key of parent. b is not changed.
+func (p *pruningRadixTrie) mergeChild(parent *node, path string, b *node) {
+	for i, a := range parent.children {
+		common := p.findCommon(a.key, b.key)
+		if common == 0 {
+			continue
+		}
+		switch {
+		case common == len(a.key) && common == len(b.key):
+			//existing ab
+			//merged   ab
+			a = p.ownChild(parent, i)
+			p.mergeNode(a, path+a.key, b)
+		case common == len(a.key):
+			//existing ab
+			//merged   abcd
+			a = p.ownChild(parent, i)
+			rest := p.own(b)
+			rest.key = b.key[common:]
+			p.mergeChild(a, path+a.key, rest)
+			recomputeMaxCount(a)
+		case common == len(b.key):
+			//existing abcd
+			//merged   ab
+			a = p.ownChild(parent, i)
+			split := p.newNode(a.key[:common], 0)
+			a.key = a.key[common:]
+			split.children = append(split.children, a)
+			p.mergeNode(split, path+split.key, b)
+			parent.children[i] = split
+		default:
+			//existing abcd
+			//merged   abef
+			a = p.ownChild(parent, i)
+			split := p.newNode(a.key[:common], 0)
+			a.key = a.key[common:]
+			split.children = append(split.children, a)
+			rest := p.own(b)
+			rest.key = b.key[common:]
+			p.mergeChild(split, path+split.key, rest)
+			recomputeMaxCount(split)
+			parent.children[i] = split
+		}
+		return
+	}
+	parent.children = append(parent.children, b)
+}
+
+// mergeNode adds the term and children of b to the owned node a holding
+// the same key, term being that key.
+func (p *pruningRadixTrie) mergeNode(a *node, term string, b *node) {
+	if b.count > 0 {
+		if a.count > 0 {
+			// counted by both tries
+			p.termCount--
+		}
+		a.count += b.count
+		for _, f := range b.forms {
+			a.addForm(f.term, f.count)
+		}
+		switch {
+		case p.cfg.halfLife > 0:
+			a.score += b.score
+		case p.cfg.scorer != nil:
+			a.score = p.cfg.scorer(term, a.count)
+		}
+	}
+	for _, child := range b.children {
+		p.mergeChild(a, term, child)
+	}
+	recomputeMaxCount(a)
+}
@@ -0,0 +1,161 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+	"time"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	a := prtrie.NewPruningRadixTrie()
+	a.AddTerm("test", 5)
+	a.AddTerm("testing", 2)
+	a.AddTerm("team", 1)
+	b := prtrie.NewPruningRadixTrie()
+	b.AddTerm("te", 3)
+	b.AddTerm("test", 4)
+	b.AddTerm("tested", 7)
+	b.AddTerm("toast", 1)
+
+	a.Merge(b)
+	assert.Equal(t, uint64(6), a.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 9},
+		{Term: "tested", Freq: 7},
+		{Term: "te", Freq: 3},
+		{Term: "testing", Freq: 2},
+		{Term: "team", Freq: 1},
+		{Term: "toast", Freq: 1},
+	}, a.TopKForPrefix("t", 10))
+	assert.Equal(t, uint64(4), b.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tested", Freq: 7},
+		{Term: "test", Freq: 4},
+		{Term: "te", Freq: 3},
+		{Term: "toast", Freq: 1},
+	}, b.TopKForPrefix("t", 10))
+}
+
+func TestMergeMatchesAddTerm(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		left, right := randomTerms(150, seed), randomTerms(150, seed+100)
+		expected := prtrie.NewPruningRadixTrie()
+		a, b := prtrie.NewPruningRadixTrie(), prtrie.NewPruningRadixTrie()
+		for _, r := range left {
+			expected.AddTerm(r.Term, r.Freq)
+			a.AddTerm(r.Term, r.Freq)
+		}
+		for _, r := range right {
+			expected.AddTerm(r.Term, r.Freq)
+			b.AddTerm(r.Term, r.Freq)
+		}
+		bTerms := b.FindAllForPrefix("")
+
+		a.Merge(b)
+		assert.Equal(t, expected.GetTotalTermCount(), a.GetTotalTermCount(), "seed %d", seed)
+		for _, prefix := range []string{"", "a", "ab", "é", "\xe4\xb8"} {
+			assert.Equal(t, expected.TopKForPrefix(prefix, 10), a.TopKForPrefix(prefix, 10), "seed %d prefix %q", seed, prefix)
+			assert.ElementsMatch(t, expected.FindAllForPrefix(prefix), a.FindAllForPrefix(prefix), "seed %d prefix %q", seed, prefix)
+		}
+
+		// the shared subtrees are copied before either trie changes them
+		for _, r := range right {
+			b.AddTerm(r.Term, 1)
+		}
+		for _, r := range left {
+			a.RemoveTerm(r.Term)
+		}
+		added := map[string]uint64{}
+		for _, r := range right {
+			added[r.Term]++
+		}
+		var updated []prtrie.Result
+		for _, r := range bTerms {
+			updated = append(updated, prtrie.Result{Term: r.Term, Freq: r.Freq + added[r.Term]})
+		}
+		assert.ElementsMatch(t, updated, b.FindAllForPrefix(""), "seed %d", seed)
+		for _, r := range a.FindAllForPrefix("") {
+			assert.Zero(t, countOf(left, r.Term), "seed %d term %q", seed, r.Term)
+		}
+	}
+}
+
+func TestMergeItself(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 2)
+	p.AddTerm("team", 1)
+	p.Merge(p)
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 4},
+		{Term: "team", Freq: 2},
+	}, p.TopKForPrefix("te", 2))
+}
+
+func TestMergeDisplayForms(t *testing.T) {
+	a := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	a.AddTerm("Go", 2)
+	b := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	b.AddTerm("go", 3)
+	b.AddTerm("GOPHER", 1)
+	a.Merge(b)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "go", Freq: 5},
+		{Term: "GOPHER", Freq: 1},
+	}, a.TopKForPrefix("G", 2))
+}
+
+func TestMergeScores(t *testing.T) {
+	a := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	a.AddTerm("test!", 1)
+	b := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	b.AddTerm("test!", 2)
+	b.AddTerm("tests", 25)
+	a.Merge(b)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test!", Freq: 3, Score: 30},
+		{Term: "tests", Freq: 25, Score: 25},
+	}, a.TopKForPrefix("te", 2))
+
+	// decaying tries are merged relative to the same landmark
+	c := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Hour))
+	c.AddTermAt("test", 8, epoch)
+	d := prtrie.NewPruningRadixTrie(prtrie.WithDecay(time.Hour))
+	d.AddTermAt("test", 2, epoch.Add(2*time.Hour))
+	d.AddTermAt("team", 3, epoch.Add(2*time.Hour))
+	c.Merge(d)
+	top := c.TopKForPrefix("te", 2)
+	assert.Equal(t, "test", top[0].Term)
+	assert.InDelta(t, 4, c.DecayedScore(top[0].Score, epoch.Add(2*time.Hour)), 1e-9)
+	assert.InDelta(t, 3, c.DecayedScore(top[1].Score, epoch.Add(2*time.Hour)), 1e-9)
+}
+
+func TestValueTrieMerge(t *testing.T) {
+	a := prtrie.NewValueTrieWithMerge(func(old, added []string) []string {
+		return append(old, added...)
+	})
+	a.AddTerm("test", 1, []string{"a"})
+	b := prtrie.NewValueTrieWithMerge(func(old, added []string) []string {
+		return append(old, added...)
+	})
+	b.AddTerm("test", 1, []string{"b"})
+	b.AddTerm("testing", 1, []string{"c"})
+	a.Merge(b)
+	value, _ := a.Value("test")
+	assert.Equal(t, []string{"a", "b"}, value)
+	value, _ = a.Value("testing")
+	assert.Equal(t, []string{"c"}, value)
+}
+
+// countOf counts the results for term.
+func countOf(results []prtrie.Result, term string) int {
+	n := 0
+	for _, r := range results {
+		if r.Term == term {
+			n++
+		}
+	}
+	return n
+}
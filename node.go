@@ -1,10 +1,28 @@
 package pruningradixtrie
 
+import "sort"
+
 type node struct {
 	key           string
 	count         uint64
 	maxChildCount uint64
-	children      []*node
+	// score and maxScore are the score of the term and the largest score
+	// in the subtree when the trie has a Scorer, zero otherwise
+	score    float64
+	maxScore float64
+	children []*node
+	// forms holds the display forms of the term when the trie normalizes keys,
+	// in descending order of count
+	forms []form
+	// gen is the generation of the trie that owns the node,
+	// nodes of other generations are shared with snapshots and copied on write
+	gen uint64
+}
+
+// form is a display form of a normalized term and how often it was added.
+type form struct {
+	term  string
+	count uint64
 }
 
 func newNode(term string, count uint64) *node {
@@ -13,3 +31,56 @@ func newNode(term string, count uint64) *node {
 		count: count,
 	}
 }
+
+// term returns the display form of the term held by n,
+// key being its full key in the trie.
+func (n *node) term(key string) string {
+	if len(n.forms) > 0 {
+		return n.forms[0].term
+	}
+	return key
+}
+
+// addForm records count more occurrences of the display form term.
+func (n *node) addForm(term string, count uint64) {
+	i := 0
+	for i < len(n.forms) && n.forms[i].term != term {
+		i++
+	}
+	if i == len(n.forms) {
+		n.forms = append(n.forms, form{term: term})
+	}
+	n.forms[i].count += count
+	// move the form up past less frequent ones, ties keep the older form first
+	for ; i > 0 && n.forms[i-1].count < n.forms[i].count; i-- {
+		n.forms[i-1], n.forms[i] = n.forms[i], n.forms[i-1]
+	}
+}
+
+// removeForms takes count occurrences away from the display forms,
+// starting with term itself and then from the least frequent forms.
+func (n *node) removeForms(term string, count uint64) {
+	for i := range n.forms {
+		if n.forms[i].term == term {
+			taken := min(count, n.forms[i].count)
+			n.forms[i].count -= taken
+			count -= taken
+			break
+		}
+	}
+	for i := len(n.forms) - 1; i >= 0 && count > 0; i-- {
+		taken := min(count, n.forms[i].count)
+		n.forms[i].count -= taken
+		count -= taken
+	}
+	forms := n.forms[:0]
+	for _, f := range n.forms {
+		if f.count > 0 {
+			forms = append(forms, f)
+		}
+	}
+	n.forms = forms
+	sort.SliceStable(n.forms, func(i, j int) bool {
+		return n.forms[j].count < n.forms[i].count
+	})
+}
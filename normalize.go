@@ -0,0 +1,65 @@
+package pruningradixtrie
+
+import (
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer maps a term, or a prefix, to the key it is stored and looked up under.
+// Terms whose keys are equal are treated as the same term.
+// It must be safe for concurrent use.
+type Normalizer func(string) string
+
+var _ Normalizer = NFC
+var _ Normalizer = NFKC
+var _ Normalizer = CaseFold
+var _ Normalizer = StripDiacritics
+
+// NFC composes characters, an e followed by a combining acute accent becomes é.
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NFKC composes characters and replaces compatibility characters,
+// e.g. the ligature "ﬁ" becomes "fi" and full width "Ａ" becomes "A".
+func NFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// CaseFold folds case so that "Straße", "STRASSE" and "strasse" match.
+func CaseFold(s string) string {
+	return cases.Fold().String(s)
+}
+
+// StripDiacritics removes combining marks so that "Café" matches "Cafe".
+// The result is in NFC.
+func StripDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	r, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return r
+}
+
+// ChainNormalizers applies the given normalizers in order.
+func ChainNormalizers(normalizers ...Normalizer) Normalizer {
+	return func(s string) string {
+		for _, n := range normalizers {
+			s = n(s)
+		}
+		return s
+	}
+}
+
+// normalize returns the key of term.
+func (p *pruningRadixTrie) normalize(term string) string {
+	if p.cfg.normalizer == nil {
+		return term
+	}
+	return p.cfg.normalizer(term)
+}
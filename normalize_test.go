@@ -0,0 +1,83 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizers(t *testing.T) {
+	assert.Equal(t, "caf\u00e9", prtrie.NFC("cafe\u0301"))
+	assert.Equal(t, "fiA", prtrie.NFKC("ﬁＡ"))
+	assert.Equal(t, "strasse", prtrie.CaseFold("STRASSE"))
+	assert.Equal(t, "strasse", prtrie.CaseFold("Straße"))
+	assert.Equal(t, "Cafe creme", prtrie.StripDiacritics("Café crème"))
+	assert.Equal(t, "cafe", prtrie.ChainNormalizers(prtrie.CaseFold, prtrie.StripDiacritics)("CAFÉ"))
+}
+
+func TestNormalizerFoldsQueries(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(
+		prtrie.ChainNormalizers(prtrie.NFKC, prtrie.CaseFold, prtrie.StripDiacritics),
+	))
+	p.AddTerm("Café", 5)
+	p.AddTerm("Cafeteria", 3)
+	p.AddTerm("café au lait", 1)
+	assert.Equal(t, uint64(3), p.GetTotalTermCount())
+	expected := []prtrie.Result{
+		{Term: "Café", Freq: 5},
+		{Term: "Cafeteria", Freq: 3},
+		{Term: "café au lait", Freq: 1},
+	}
+	for _, prefix := range []string{"cafe", "CAFE", "café", "Café", "caf"} {
+		assert.Equal(t, expected, p.TopKForPrefix(prefix, 3), "expected %q to match all forms", prefix)
+	}
+	assert.Equal(t, expected[:1], p.TopKForPrefix("cafe", 1))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "café au lait", Freq: 1},
+	}, p.TopKForPrefix("CAFÉ AU", 3))
+	assert.ElementsMatch(t, expected, p.FindAllForPrefix("CAF"))
+}
+
+func TestNormalizerAggregatesDisplayForms(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Go", 2)
+	p.AddTerm("go", 1)
+	assert.Equal(t, uint64(1), p.GetTotalTermCount(), "expected forms of the same key to be one term")
+	assert.Equal(t, []prtrie.Result{{Term: "Go", Freq: 3}}, p.TopKForPrefix("g", 1))
+
+	p.AddTerm("go", 2)
+	assert.Equal(t, []prtrie.Result{{Term: "go", Freq: 5}}, p.TopKForPrefix("G", 1), "expected most frequent form to be returned")
+
+	p.AddTerm("GO", 1)
+	count, ok := p.DecrementTerm("go", 2)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), count)
+	assert.Equal(t, []prtrie.Result{{Term: "Go", Freq: 4}}, p.TopKForPrefix("g", 1), "expected the form decremented to lose its place")
+
+	old, ok := p.SetTerm("GO", 10)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), old)
+	assert.Equal(t, []prtrie.Result{{Term: "GO", Freq: 10}}, p.TopKForPrefix("g", 1))
+
+	count, ok = p.RemoveTerm("gO")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), count)
+	assert.Empty(t, p.TopKForPrefix("g", 1))
+	p.AddTerm("go", 1)
+	assert.Equal(t, []prtrie.Result{{Term: "go", Freq: 1}}, p.TopKForPrefix("g", 1), "expected forms to be forgotten on removal")
+}
+
+func TestNormalizerKeepsFormsWhenMerging(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Testing", 5)
+	p.AddTerm("TESTER", 10)
+	p.RemoveTerm("tester")
+	assert.Equal(t, []prtrie.Result{{Term: "Testing", Freq: 5}}, p.TopKForPrefix("test", 2))
+}
+
+func TestNormalizerEmptyKey(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.StripDiacritics))
+	p.AddTerm("́", 1)
+	assert.Equal(t, uint64(0), p.GetTotalTermCount(), "expected terms normalized to nothing to be ignored")
+}
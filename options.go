@@ -0,0 +1,61 @@
+package pruningradixtrie
+
+import "time"
+
+// Option configures a trie created by NewPruningRadixTrie.
+type Option func(*config)
+
+type config struct {
+	byteKeys   bool
+	normalizer Normalizer
+	scorer     Scorer
+	halfLife   time.Duration
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithByteKeys splits node keys on any byte instead of on rune boundaries.
+// By default every node key is kept valid UTF-8, this option is only
+// meant for terms that are arbitrary bytes rather than text.
+func WithByteKeys() Option {
+	return func(c *config) {
+		c.byteKeys = true
+	}
+}
+
+// WithNormalizer stores terms under the key returned by n and normalizes
+// prefixes the same way before looking them up, while results keep
+// the form terms were added with. When several forms share a key their
+// counts are summed and the most frequent form is returned.
+func WithNormalizer(n Normalizer) Option {
+	return func(c *config) {
+		c.normalizer = n
+	}
+}
+
+// WithScorer ranks terms by the score s gives them instead of by frequency,
+// frequency and then term breaking ties between equal scores.
+// Scores are computed from the normalized term whenever its count changes
+// and returned in Result.Score.
+func WithScorer(s Scorer) Option {
+	return func(c *config) {
+		c.scorer = s
+	}
+}
+
+// WithDecay makes the counts added to a term fade over time, losing half of
+// their weight every halfLife, and ranks terms by their decayed count, see
+// AddTermAt. Result.Freq keeps the plain sum of the counts added.
+// Decay replaces any Scorer. Only counts are kept by WriteTo and taken by
+// NewFromResults, terms read or built that way start decaying from then on.
+func WithDecay(halfLife time.Duration) Option {
+	return func(c *config) {
+		c.halfLife = halfLife
+	}
+}
@@ -0,0 +1,107 @@
+package pruningradixtrie
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// All returns an iterator over every term of the trie and its frequency
+// in lexicographic (byte) order of the keys, see Range.
+func (p *pruningRadixTrie) All() iter.Seq2[string, uint64] {
+	return p.Range("", "")
+}
+
+// Range returns an iterator over the terms from from included to to excluded
+// and their frequencies, in lexicographic (byte) order of the keys.
+// An empty to means no upper bound. With a normalizer from and to are
+// normalized and compared to the keys, while the display forms are yielded.
+// Children are kept in frequency order for TopKForPrefix, so they are sorted
+// by key on the fly as the iteration goes, and subtrees entirely out of the
+// range are skipped. The trie must not be updated during the iteration.
+func (p *pruningRadixTrie) Range(from, to string) iter.Seq2[string, uint64] {
+	from, to = p.normalize(from), p.normalize(to)
+	return func(yield func(string, uint64) bool) {
+		ascend("", p.trie, from, to, func(key string, n *node) bool {
+			return yield(n.term(key), n.count)
+		})
+	}
+}
+
+// Successor returns the first term after term in lexicographic order.
+func (p *pruningRadixTrie) Successor(term string) (Result, bool) {
+	term = p.normalize(term)
+	var result Result
+	found := false
+	ascend("", p.trie, term, "", func(key string, n *node) bool {
+		if key == term {
+			return true
+		}
+		result = Result{Term: n.term(key), Freq: n.count, Score: n.score}
+		found = true
+		return false
+	})
+	return result, found
+}
+
+// Predecessor returns the last term before term in lexicographic order.
+func (p *pruningRadixTrie) Predecessor(term string) (Result, bool) {
+	term = p.normalize(term)
+	var result Result
+	found := false
+	descend("", p.trie, term, func(key string, n *node) bool {
+		result = Result{Term: n.term(key), Freq: n.count, Score: n.score}
+		found = true
+		return false
+	})
+	return result, found
+}
+
+// byKey returns the children of n sorted by key.
+func byKey(n *node) []*node {
+	children := slices.Clone(n.children)
+	slices.SortFunc(children, func(a, b *node) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return children
+}
+
+// ascend calls fn in ascending order for the terms of the subtree of n
+// from from included to to excluded, path being the key above n.
+// Returns false if fn stopped the iteration or the end of the range was reached.
+func ascend(path string, n *node, from, to string, fn func(key string, n *node) bool) bool {
+	key := path + n.key
+	if to != "" && key >= to {
+		// every term below starts with key
+		return false
+	}
+	if key < from && !strings.HasPrefix(from, key) {
+		return true
+	}
+	if n.count > 0 && key >= from && !fn(key, n) {
+		return false
+	}
+	for _, child := range byKey(n) {
+		if !ascend(key, child, from, to, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// descend calls fn in descending order for the terms of the subtree of n
+// before before, path being the key above n.
+// Returns false if fn stopped the iteration.
+func descend(path string, n *node, before string, fn func(key string, n *node) bool) bool {
+	key := path + n.key
+	if key >= before {
+		return true
+	}
+	children := byKey(n)
+	for i := len(children) - 1; i >= 0; i-- {
+		if !descend(key, children[i], before, fn) {
+			return false
+		}
+	}
+	return n.count == 0 || fn(key, n)
+}
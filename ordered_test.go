@@ -0,0 +1,112 @@
+package pruningradixtrie_test
+
+import (
+	"sort"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+// collect gathers the terms yielded by seq.
+func collect(seq func(func(string, uint64) bool)) []prtrie.Result {
+	var results []prtrie.Result
+	for term, freq := range seq {
+		results = append(results, prtrie.Result{Term: term, Freq: freq})
+	}
+	return results
+}
+
+func TestAll(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 1)
+	p.AddTerm("team", 5)
+	p.AddTerm("testing", 9)
+	p.AddTerm("abc", 2)
+	p.AddTerm("te", 3)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "abc", Freq: 2},
+		{Term: "te", Freq: 3},
+		{Term: "team", Freq: 5},
+		{Term: "test", Freq: 1},
+		{Term: "testing", Freq: 9},
+	}, collect(p.All()))
+	// the frequency order used by TopKForPrefix is unchanged
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 9},
+		{Term: "team", Freq: 5},
+	}, p.TopKForPrefix("te", 2))
+
+	var first []string
+	for term := range p.All() {
+		first = append(first, term)
+		if len(first) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"abc", "te"}, first)
+	assert.Empty(t, collect(prtrie.NewPruningRadixTrie().All()))
+}
+
+func TestRange(t *testing.T) {
+	results := randomTerms(300, 7)
+	p := prtrie.NewFromResults(results)
+	all := collect(p.All())
+	assert.True(t, sort.SliceIsSorted(all, func(i, j int) bool {
+		return all[i].Term < all[j].Term
+	}))
+	assert.Len(t, all, int(p.GetTotalTermCount()))
+	for _, bounds := range [][2]string{{"", ""}, {"a", "b"}, {"ab", "ab "}, {"b", ""}, {"é", "中"}, {"\xe4", "\xe4\xb8\xad"}, {"z", "a"}} {
+		var expected []prtrie.Result
+		for _, r := range all {
+			if r.Term >= bounds[0] && (bounds[1] == "" || r.Term < bounds[1]) {
+				expected = append(expected, r)
+			}
+		}
+		assert.Equal(t, expected, collect(p.Range(bounds[0], bounds[1])), "range %q", bounds)
+	}
+}
+
+func TestSuccessorPredecessor(t *testing.T) {
+	p := prtrie.NewFromResults(randomTerms(300, 8))
+	all := collect(p.All())
+	for i, r := range all {
+		next, ok := p.Successor(r.Term)
+		if i+1 < len(all) {
+			assert.True(t, ok)
+			assert.Equal(t, all[i+1], next, "successor of %q", r.Term)
+		} else {
+			assert.False(t, ok)
+		}
+		prev, ok := p.Predecessor(r.Term)
+		if i > 0 {
+			assert.True(t, ok)
+			assert.Equal(t, all[i-1], prev, "predecessor of %q", r.Term)
+		} else {
+			assert.False(t, ok)
+		}
+		// terms not in the trie
+		next, ok = p.Successor(r.Term + "\x00")
+		assert.Equal(t, i+1 < len(all), ok)
+		if ok {
+			assert.Equal(t, all[i+1], next)
+		}
+	}
+	first, ok := p.Successor("")
+	assert.True(t, ok)
+	assert.Equal(t, all[0], first)
+	last, ok := p.Predecessor("\xff")
+	assert.True(t, ok)
+	assert.Equal(t, all[len(all)-1], last)
+}
+
+func TestRangeNormalizer(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Beta", 1)
+	p.AddTerm("alpha", 2)
+	p.AddTerm("GAMMA", 3)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "Beta", Freq: 1},
+		{Term: "GAMMA", Freq: 3},
+	}, collect(p.Range("B", "H")))
+}
@@ -0,0 +1,29 @@
+package pruningradixtrie
+
+// newTopKResults returns the result set to collect the top k terms for prefix.
+// Unless after is the zero Result, only the terms ranking below it are kept,
+// so passing the last result of a page as after returns the next page.
+// The result set never holds more than k terms and subtrees are pruned
+// as soon as it is full, instead of collecting every result skipped.
+func newTopKResults(prefix string, k int, after Result) ResultSet {
+	var results ResultSet = NewResultHeap()
+	if len(prefix) > 5 || k < 1000 {
+		results = NewBSResultSet()
+	}
+	if after.Freq == 0 {
+		return results
+	}
+	return &afterResults{ResultSet: results, after: after}
+}
+
+// afterResults drops the results that do not rank below after.
+type afterResults struct {
+	ResultSet
+	after Result
+}
+
+func (a *afterResults) PushResult(r Result) {
+	if ranksBelow(r, a.after) {
+		a.ResultSet.PushResult(r)
+	}
+}
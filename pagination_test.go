@@ -0,0 +1,68 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pages collects every page of size k for prefix.
+func pages(trie prtrie.ReadOnlyTrie, prefix string, k int) []prtrie.Result {
+	var all []prtrie.Result
+	var after prtrie.Result
+	for {
+		page := trie.TopKForPrefixAfter(prefix, k, after)
+		all = append(all, page...)
+		if len(page) < k {
+			return all
+		}
+		after = page[len(page)-1]
+	}
+}
+
+func TestTopKForPrefixAfter(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 10)
+	p.AddTerm("tester", 7)
+	p.AddTerm("testing", 7)
+	p.AddTerm("tested", 3)
+	p.AddTerm("team", 20)
+
+	assert.Equal(t, p.TopKForPrefix("te", 2), p.TopKForPrefixAfter("te", 2, prtrie.Result{}))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 7},
+		{Term: "testing", Freq: 7},
+	}, p.TopKForPrefixAfter("te", 2, prtrie.Result{Term: "test", Freq: 10}))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 7},
+		{Term: "tested", Freq: 3},
+	}, p.TopKForPrefixAfter("te", 2, prtrie.Result{Term: "tester", Freq: 7}))
+	assert.Empty(t, p.TopKForPrefixAfter("te", 2, prtrie.Result{Term: "tested", Freq: 3}))
+	assert.Nil(t, p.TopKForPrefixAfter("te", 0, prtrie.Result{Term: "test", Freq: 10}))
+}
+
+func TestTopKForPrefixAfterPages(t *testing.T) {
+	results := randomTerms(300, 3)
+	p := prtrie.NewFromResults(results)
+	frozen, err := p.Freeze()
+	require.NoError(t, err)
+	for _, prefix := range []string{"", "a", "é", "\xe4\xb8"} {
+		all := p.TopKForPrefix(prefix, len(results))
+		for _, k := range []int{1, 3, 10} {
+			assert.Equal(t, all, pages(p, prefix, k), "prefix %q k %d", prefix, k)
+			assert.Equal(t, all, pages(frozen, prefix, k), "prefix %q k %d", prefix, k)
+		}
+	}
+}
+
+func TestTopKForPrefixAfterDisplayForms(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Go", 5)
+	p.AddTerm("go", 3)
+	p.AddTerm("gopher", 5)
+	p.AddTerm("Gold", 1)
+	all := p.TopKForPrefix("g", 10)
+	assert.Equal(t, all, pages(p, "g", 1))
+}
@@ -0,0 +1,40 @@
+package pruningradixtrie
+
+import "strings"
+
+// LongestPrefixOf returns the longest term of the trie that s starts with,
+// and whether there is one.
+func (p *pruningRadixTrie) LongestPrefixOf(s string) (Result, bool) {
+	prefixes := p.AllPrefixesOf(s)
+	if len(prefixes) == 0 {
+		return Result{}, false
+	}
+	return prefixes[len(prefixes)-1], true
+}
+
+// AllPrefixesOf returns every term of the trie that s starts with,
+// from the shortest to the longest. Only the nodes on the path of s are
+// visited, a radix tree holding at most one child whose key s continues with.
+func (p *pruningRadixTrie) AllPrefixesOf(s string) []Result {
+	s = p.normalize(s)
+	var results []Result
+	key := ""
+	cur := p.trie
+	for {
+		var next *node
+		for _, child := range cur.children {
+			if strings.HasPrefix(s[len(key):], child.key) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return results
+		}
+		key += next.key
+		if next.count > 0 {
+			results = append(results, Result{Term: next.term(key), Freq: next.count, Score: next.score})
+		}
+		cur = next
+	}
+}
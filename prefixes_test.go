@@ -0,0 +1,64 @@
+package pruningradixtrie_test
+
+import (
+	"strings"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllPrefixesOf(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("new", 3)
+	p.AddTerm("new york", 5)
+	p.AddTerm("new york city", 2)
+	p.AddTerm("newark", 1)
+	p.AddTerm("ne", 4)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "ne", Freq: 4},
+		{Term: "new", Freq: 3},
+		{Term: "new york", Freq: 5},
+	}, p.AllPrefixesOf("new york state"))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "ne", Freq: 4},
+		{Term: "new", Freq: 3},
+		{Term: "new york", Freq: 5},
+		{Term: "new york city", Freq: 2},
+	}, p.AllPrefixesOf("new york city"))
+	assert.Empty(t, p.AllPrefixesOf("n"))
+	assert.Empty(t, p.AllPrefixesOf(""))
+
+	longest, ok := p.LongestPrefixOf("newark airport")
+	assert.True(t, ok)
+	assert.Equal(t, prtrie.Result{Term: "newark", Freq: 1}, longest)
+	longest, ok = p.LongestPrefixOf("news")
+	assert.True(t, ok)
+	assert.Equal(t, prtrie.Result{Term: "new", Freq: 3}, longest)
+	_, ok = p.LongestPrefixOf("boston")
+	assert.False(t, ok)
+}
+
+func TestAllPrefixesOfMatchesScan(t *testing.T) {
+	results := randomTerms(300, 9)
+	p := prtrie.NewFromResults(results)
+	all := p.FindAllForPrefix("")
+	for _, r := range results {
+		s := r.Term + "ab"
+		var expected []prtrie.Result
+		for _, term := range all {
+			if strings.HasPrefix(s, term.Term) {
+				expected = append(expected, term)
+			}
+		}
+		assert.ElementsMatch(t, expected, p.AllPrefixesOf(s), "prefixes of %q", s)
+	}
+}
+
+func TestAllPrefixesOfNormalizer(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("New York", 2)
+	longest, ok := p.LongestPrefixOf("NEW YORK CITY")
+	assert.True(t, ok)
+	assert.Equal(t, prtrie.Result{Term: "New York", Freq: 2}, longest)
+}
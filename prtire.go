@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Result struct {
 	Term string
 	Freq uint64
+	// Score is the score of the term when the trie has a Scorer, zero otherwise
+	// and then left out of JSON
+	Score float64 `json:",omitempty"`
 }
 
 type ResultSet interface {
@@ -19,24 +24,42 @@ type ResultSet interface {
 	Len() int
 }
 
+// ReadOnlyTrie is the query side of a PruningRadixTrie.
+type ReadOnlyTrie interface {
+	TopKForPrefix(prefix string, k int) []Result
+	TopKForPrefixAfter(prefix string, k int, after Result) []Result
+	FindAllForPrefix(prefix string) []Result
+	WalkPrefix(prefix string, fn func(Result) bool)
+	GetTotalTermCount() uint64
+}
+
 type PruningRadixTrie interface {
+	ReadOnlyTrie
 	AddTerm(term string, count uint64)
-	TopKForPrefix(prefix string, k int) []Result
-	// FindAllTForPrefix(prefix string) []Result
+	RemoveTerm(term string) (uint64, bool)
+	DecrementTerm(term string, by uint64) (uint64, bool)
+	SetTerm(term string, count uint64) (uint64, bool)
 }
 
 type pruningRadixTrie struct {
 	trie      *node
 	termCount uint64
+	cfg       config
+	gen       uint64
+	// landmark is the time the scores of a decaying trie are relative to
+	landmark time.Time
 }
 
 var _ PruningRadixTrie = &pruningRadixTrie{}
 var _ fmt.Stringer = &pruningRadixTrie{}
 
-func NewPruningRadixTrie() *pruningRadixTrie {
-	return &pruningRadixTrie{
-		trie: newNode("", 0),
+func NewPruningRadixTrie(opts ...Option) *pruningRadixTrie {
+	p := &pruningRadixTrie{
+		cfg: newConfig(opts),
+		gen: newGeneration(),
 	}
+	p.trie = p.newNode("", 0)
+	return p
 }
 
 func (p *pruningRadixTrie) GetTotalTermCount() uint64 {
@@ -44,11 +67,39 @@ func (p *pruningRadixTrie) GetTotalTermCount() uint64 {
 }
 
 func (p *pruningRadixTrie) AddTerm(term string, count uint64) {
-	if term == "" || count == 0 {
+	if p.cfg.halfLife > 0 {
+		p.AddTermAt(term, count, time.Now())
+		return
+	}
+	p.addTermAt(term, count, time.Time{})
+}
+
+// addTermAt adds term, at being the time of the update in a decaying trie.
+func (p *pruningRadixTrie) addTermAt(term string, count uint64, at time.Time) {
+	key := p.normalize(term)
+	if key == "" || count == 0 {
 		return
 	}
 	var list []*node
-	p.addTerm(p.trie, term, count, 0, list)
+	p.trie = p.own(p.trie)
+	p.addTerm(p.trie, key, count, 0, list)
+	if p.cfg.normalizer != nil || p.scored() {
+		path := p.ownPath(p.findPath(key))
+		n := path[len(path)-1]
+		if p.cfg.normalizer != nil {
+			n.addForm(term, count)
+		}
+		if p.scored() {
+			if p.cfg.halfLife > 0 {
+				n.score += p.weight(count, at)
+			} else {
+				p.score(path, n.count-count)
+			}
+			for i := len(path) - 1; i >= 0; i-- {
+				recomputeMaxCount(path[i])
+			}
+		}
+	}
 }
 
 func (p *pruningRadixTrie) addTerm(
@@ -58,12 +109,15 @@ func (p *pruningRadixTrie) addTerm(
 	level int,
 	list []*node,
 ) {
+	// cur and every node changed below it are owned by the trie,
+	// see Snapshot
 	list = append(list, cur)
 
 	for i, child := range cur.children {
 		key := child.key
-		common := findCommon(key, term)
+		common := p.findCommon(key, term)
 		if common > 0 {
+			child = p.ownChild(cur, i)
 			if common == len(term) && common == len(key) {
 				//term already existed
 				//existing ab
@@ -76,8 +130,6 @@ func (p *pruningRadixTrie) addTerm(
 				child.maxChildCount = newMax
 				// todo: see if we can only update nodes in path
 				// where max count was updated
-				// list = append(list, child)
-				// updateMaxCounts(list, child.count)
 				reorder(list, newMax)
 
 			} else if common == len(term) {
@@ -88,19 +140,15 @@ func (p *pruningRadixTrie) addTerm(
 				// 0123 -> [:2]:[2:]
 				// abcd -> ab:cd
 
-				// split with unicode
-				// https://stackoverflow.com/a/56129336
-				prefix := term
-				suffix := key[common:]
-				oldChildCount := child.count
-				newChild := newNode(prefix, count)
-				newMax := max(cur.count, cur.maxChildCount)
+				// the old child keeps its counts and subtree and moves under the new term
+				child.key = key[common:]
+				newChild := p.newNode(term, count)
+				newChild.children = append(newChild.children, child)
+				newMax := max(count, child.maxChildCount)
 				newChild.maxChildCount = newMax
 				cur.children[i] = newChild
-				// updateChildren(cur)
 				reorder(list, newMax)
-				// adding the old child back under the split term as if it was a new term added
-				p.addTerm(newChild, suffix, oldChildCount, level+1, list)
+				p.termCount++
 			} else if common == len(key) {
 				//if oldkey shorter (==common), then recursive addTerm (clause1)
 				//existing: te
@@ -115,7 +163,7 @@ func (p *pruningRadixTrie) addTerm(
 				splitSuffix := key[common:]
 				// create a new node of the common substrings
 				// the count is zero since the intersection was never explicitly added prior
-				split := newNode(commonPrefix, 0)
+				split := p.newNode(commonPrefix, 0)
 				split.children = append(split.children, child)
 				split.maxChildCount = max(child.maxChildCount, child.count, count)
 				child.key = splitSuffix
@@ -127,26 +175,47 @@ func (p *pruningRadixTrie) addTerm(
 		}
 	}
 
-	newChild := newNode(term, count)
+	newChild := p.newNode(term, count)
+	newChild.maxChildCount = count
 	cur.children = append(cur.children, newChild)
-	list = append(list, newChild)
-	updateMaxCounts(list, count)
-	updateChildren(cur)
+	// raising the max counts of the ancestors can change the order of their children too
+	reorder(list, count)
 	p.termCount++
 }
 
-func findCommon(key, term string) int {
+// findCommon returns the length in bytes of the common prefix of key and term.
+// Unless the trie uses byte keys the length is moved back to a rune boundary,
+// so splitting either string there never cuts a multi-byte rune in half.
+// Terms sharing only the leading bytes of their first rune have nothing in common
+// and become siblings.
+func (p *pruningRadixTrie) findCommon(key, term string) int {
 	common := 0
-	// TODO: make it unicode compatible.
 	for i := 0; i < min(len(key), len(term)); i++ {
 		if key[i] != term[i] {
 			break
 		}
 		common++
 	}
+	if p.cfg.byteKeys {
+		return common
+	}
+	for common > 0 && (midRune(key, common) || midRune(term, common)) {
+		common--
+	}
 	return common
 }
 
+// midRune reports whether i falls inside a multi-byte rune of s.
+func midRune(s string, i int) bool {
+	return i < len(s) && !utf8.RuneStart(s[i])
+}
+
+// partialRune reports whether prefix ends before its first rune is complete,
+// in which case several siblings starting with different runes can match it.
+func partialRune(prefix string) bool {
+	return prefix != "" && !utf8.FullRuneInString(prefix)
+}
+
 func reorder(nodes []*node, count uint64) {
 	for i := len(nodes) - 1; i >= 0; i-- {
 		n := nodes[i]
@@ -157,76 +226,202 @@ func reorder(nodes []*node, count uint64) {
 	}
 }
 
-// updateChildren sort children of a node in descending order
+// updateChildren sort children of a node in descending order of maxScore
+// and then maxChildCount, children with the same ones in lexicographic order
+// of their keys
 func updateChildren(n *node) {
 	children := n.children
 	sort.Slice(children, func(i, j int) bool {
-		return children[j].maxChildCount < children[i].maxChildCount
-	})
-}
-
-func updateMaxCounts(nodes []*node, count uint64) {
-	// TODO: check if update max left to root is more correct
-	for _, n := range nodes {
-		if count > n.maxChildCount {
-			n.maxChildCount = count
+		if children[i].maxScore != children[j].maxScore {
+			return children[j].maxScore < children[i].maxScore
 		}
-	}
+		if children[i].maxChildCount != children[j].maxChildCount {
+			return children[j].maxChildCount < children[i].maxChildCount
+		}
+		return children[i].key < children[j].key
+	})
 }
 
 // TopKForPrefix implements PruningRadixTrie.
+// Results are ordered by descending frequency, terms with the same frequency
+// in lexicographic order.
+// Prefixes are matched byte for byte, so a prefix ending in an incomplete
+// UTF-8 sequence matches every term whose next rune starts with those bytes.
 func (p *pruningRadixTrie) TopKForPrefix(prefix string, k int) []Result {
+	return p.TopKForPrefixAfter(prefix, k, Result{})
+}
+
+// TopKForPrefixAfter implements PruningRadixTrie, see newTopKResults.
+func (p *pruningRadixTrie) TopKForPrefixAfter(prefix string, k int, after Result) []Result {
 	if k <= 0 {
 		return nil
 	}
-	results := NewResultHeap()
-	if len(prefix) > 5 || k < 1000 {
-		results = NewBSResultSet()
-	}
-	topKForPrefix(prefix, "", p.trie, k, results)
+	results := newTopKResults(prefix, k, after)
+	// display forms do not follow the order of the keys
+	ordered := p.cfg.normalizer == nil
+	topKForPrefix(p.normalize(prefix), "", p.trie, k, results, ordered)
 	return results.Results()
 }
 
 // TopKForPrefix implements PruningRadixTrie.
-func topKForPrefix(prefix, path string, cur *node, k int, results ResultSet) {
-	if results.Len() == k && cur.maxChildCount <= results.PeekMinResult().Freq {
+// ordered tells whether results hold the keys of the trie, in which case
+// subtrees are also pruned on ties since none of their terms come before
+// their path in lexicographic order.
+func topKForPrefix(prefix, path string, cur *node, k int, results ResultSet, ordered bool) {
+	if !canRankIn(results, k, cur.maxScore, cur.maxChildCount, path, "", ordered) {
 		return
 	}
 
 	noPrefix := prefix == ""
+	// siblings can only share the bytes of an incomplete rune
+	siblings := noPrefix || partialRune(prefix)
 	if len(cur.children) == 0 {
 		return
 	}
 	for _, child := range cur.children {
 		key := child.key
-		if results.Len() == k && child.count <= results.PeekMinResult().Freq && child.maxChildCount <= results.PeekMinResult().Freq {
-			if noPrefix {
+		if !canRankIn(results, k, child.maxScore, child.maxChildCount, path, key, ordered) {
+			if lowest := results.PeekMinResult(); child.maxScore == lowest.Score && child.maxChildCount == lowest.Freq {
+				// a sibling with the same count may still win the tie
 				continue
 			}
+			// siblings come in descending order of maxScore and maxChildCount
 			break
 		}
 		if noPrefix || strings.HasPrefix(key, prefix) {
 			if child.count > 0 {
-				result := Result{Term: path + key, Freq: child.count}
+				result := Result{Term: child.term(path + key), Freq: child.count, Score: child.score}
 				results.PushResult(result)
 				if results.Len() > k {
 					results.PopResult()
 				}
 			}
 			if len(child.children) > 0 {
-				topKForPrefix("", path+key, child, k, results)
+				topKForPrefix("", path+key, child, k, results, ordered)
 			}
-			if !noPrefix {
+			if !siblings {
 				break
 			}
 		} else if strings.HasPrefix(prefix, key) {
 			if len(child.children) > 0 {
-				topKForPrefix(prefix[len(key):], path+key, child, k, results)
+				topKForPrefix(prefix[len(key):], path+key, child, k, results, ordered)
 			}
 		}
 	}
 }
 
+// ranksBelow reports whether a comes after b in results, having a lower score,
+// or the same one and a lower frequency, or the same ones and a greater term.
+func ranksBelow(a, b Result) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	if a.Freq != b.Freq {
+		return a.Freq < b.Freq
+	}
+	return a.Term > b.Term
+}
+
+// canRankIn reports whether a subtree whose largest score and count are maxScore
+// and maxCount and whose terms start with path+key may hold a term that makes it
+// into the top k results. Unless ordered, ties on the count are assumed to be won.
+func canRankIn[S ~string | ~[]byte](results ResultSet, k int, maxScore float64, maxCount uint64, path, key S, ordered bool) bool {
+	if results.Len() < k {
+		return true
+	}
+	lowest := results.PeekMinResult()
+	if maxScore != lowest.Score {
+		return maxScore > lowest.Score
+	}
+	if maxCount != lowest.Freq {
+		return maxCount > lowest.Freq
+	}
+	return !ordered || !atLeast(path, key, lowest.Term)
+}
+
+// atLeast reports whether path+key >= term without concatenating them.
+func atLeast[S ~string | ~[]byte](path, key S, term string) bool {
+	for i := 0; i < len(term); i++ {
+		var c byte
+		if i < len(path) {
+			c = path[i]
+		} else if i-len(path) < len(key) {
+			c = key[i-len(path)]
+		} else {
+			// path+key is a proper prefix of term
+			return false
+		}
+		if c != term[i] {
+			return c > term[i]
+		}
+	}
+	return true
+}
+
+// FindAllForPrefix implements PruningRadixTrie.
+// Returns every term starting with prefix, in the order described by WalkPrefix.
+func (p *pruningRadixTrie) FindAllForPrefix(prefix string) []Result {
+	var results []Result
+	p.WalkPrefix(prefix, func(r Result) bool {
+		results = append(results, r)
+		return true
+	})
+	return results
+}
+
+// WalkPrefix implements PruningRadixTrie.
+// Calls fn for every term starting with prefix without materializing them,
+// stopping as soon as fn returns false.
+// Terms are visited depth first, a term before any of its extensions,
+// and siblings follow the child order of the trie (descending maxChildCount,
+// or maxScore with a Scorer).
+// This is not a total ordering by frequency, use TopKForPrefix for that.
+func (p *pruningRadixTrie) WalkPrefix(prefix string, fn func(Result) bool) {
+	walkPrefix(p.normalize(prefix), "", p.trie, fn)
+}
+
+// walkPrefix descends to the nodes matching prefix the same way topKForPrefix does
+// and walks the matching subtrees. Returns false if fn stopped the walk.
+func walkPrefix(prefix, path string, cur *node, fn func(Result) bool) bool {
+	if prefix == "" {
+		for _, child := range cur.children {
+			if !walk(path, child, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	partial := partialRune(prefix)
+	for _, child := range cur.children {
+		key := child.key
+		if strings.HasPrefix(key, prefix) {
+			if !walk(path, child, fn) {
+				return false
+			}
+			if !partial {
+				return true
+			}
+		} else if strings.HasPrefix(prefix, key) {
+			return walkPrefix(prefix[len(key):], path+key, child, fn)
+		}
+	}
+	return true
+}
+
+// walk calls fn for every term in the subtree of n, n included.
+func walk(path string, n *node, fn func(Result) bool) bool {
+	term := path + n.key
+	if n.count > 0 && !fn(Result{Term: n.term(term), Freq: n.count, Score: n.score}) {
+		return false
+	}
+	for _, child := range n.children {
+		if !walk(term, child, fn) {
+			return false
+		}
+	}
+	return true
+}
+
 // String the string representation of the trie in tree like format
 // [0, 777]
 //
@@ -1,12 +1,8 @@
 package pruningradixtrie_test
 
 import (
-	"archive/zip"
-	"errors"
+	"bytes"
 	"fmt"
-	"io"
-	"strconv"
-	"strings"
 	"testing"
 
 	prtrie "github.com/elielamora/pruningradixtrie"
@@ -32,6 +28,45 @@ func BenchmarkPruningRadixTrie_Insert(b *testing.B) {
 	}
 }
 
+func BenchmarkPruningRadixTrie_AddAllTerms(b *testing.B) {
+	entries, err := getEntries()
+	if err != nil {
+		b.Fatalf("unexpected error: %s", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := prtrie.NewPruningRadixTrie()
+		for _, entry := range entries {
+			p.AddTerm(entry.Term, entry.Freq)
+		}
+	}
+}
+
+func BenchmarkPruningRadixTrie_NewFromResults(b *testing.B) {
+	entries, err := getEntries()
+	if err != nil {
+		b.Fatalf("unexpected error: %s", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prtrie.NewFromResults(entries)
+	}
+}
+
+func BenchmarkPruningRadixTrie_ReadFrom(b *testing.B) {
+	data := &bytes.Buffer{}
+	if _, err := prtrie.NewFromResults(randomTerms(100000, 1)).WriteTo(data); err != nil {
+		b.Fatalf("unexpected error: %s", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader(data.Bytes())); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
 func BenchmarkPruningRadixTrie_TopKMicrosoft(b *testing.B) {
 	entries, err := getEntries()
 	if err != nil {
@@ -68,58 +103,5 @@ func _TestVisualize(t *testing.T) {
 }
 
 func getEntries() ([]prtrie.Result, error) {
-	termsString, err := unzip()
-	if err != nil {
-		return nil, err
-	}
-	terms := strings.Split(termsString, "\r\n")
-	var results []prtrie.Result
-	for _, term := range terms {
-		if term == "" {
-			continue
-		}
-		x := strings.Split(term, "\t")
-		if len(x) != 2 {
-			return nil, errors.New(fmt.Sprintf("unexpected format, expected 2 tab separated values but got %d: %s", len(x), term))
-		}
-		freq, err := strconv.ParseUint(x[1], 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		results = append(results, prtrie.Result{
-			Term: x[0],
-			Freq: freq,
-		})
-	}
-	return results, nil
-}
-
-// unzip unzips the bundled test terms file
-func unzip() (string, error) {
-	r, err := zip.OpenReader("./terms.zip")
-	if err != nil {
-		return "", errors.Join(errors.New("tried opening the zip reader"), err)
-	}
-
-	for _, f := range r.File {
-		if f.Name != "terms.txt" {
-			return "", errors.New(fmt.Sprintf(
-				"expected 'terms.txt' but got %s",
-				f.Name,
-			))
-		}
-		rc, err := f.Open()
-		if err != nil {
-			return "", err
-		}
-		defer rc.Close()
-
-		strbuilder := &strings.Builder{}
-		_, err = io.Copy(strbuilder, rc)
-		if err != nil {
-			return "", err
-		}
-		return strbuilder.String(), nil
-	}
-	return "", nil
+	return prtrie.LoadTermsZip("./terms.zip")
 }
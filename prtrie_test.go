@@ -6,6 +6,7 @@ import (
 
 	prtrie "github.com/elielamora/pruningradixtrie"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrtrieEmpty(t *testing.T) {
@@ -200,11 +201,8 @@ func TestPrtrieGetManyChildrenTermsSameFrequency(t *testing.T) {
 	p.AddTerm("abcdef", 1)
 	p.AddTerm("abcdefg", 1)
 	p.AddTerm("abcdefh", 1)
-	// TODO: flaky test, if we change the ResultSet results with equal frequency might
-	// be sorted differently
+	// ties are broken by term
 	assert.Equal(t, []prtrie.Result{
-		// {Term: "abcd", Freq: 1},
-		// {Term: "abc", Freq: 1},
 		{Term: "abc", Freq: 1},
 		{Term: "abcd", Freq: 1},
 	}, p.TopKForPrefix("ab", 2), "expected all top terms")
@@ -277,6 +275,96 @@ func TestPrtrieString(t *testing.T) {
 	}, "\n"), p.String())
 }
 
+func TestPrtrieFindAllForPrefix(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	assert.Empty(t, p.FindAllForPrefix(""))
+	p.AddTerm("test", 1)
+	p.AddTerm("testing", 2)
+	p.AddTerm("tester", 5)
+	p.AddTerm("food", 3)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 1},
+		{Term: "tester", Freq: 5},
+		{Term: "testing", Freq: 2},
+		{Term: "food", Freq: 3},
+	}, p.FindAllForPrefix(""), "expected all terms in trie order")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 1},
+		{Term: "tester", Freq: 5},
+		{Term: "testing", Freq: 2},
+	}, p.FindAllForPrefix("te"), "expected all terms with the common prefix")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 5},
+	}, p.FindAllForPrefix("teste"), "expected terms below a split node")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 2},
+	}, p.FindAllForPrefix("testi"), "expected terms below a split node")
+	assert.Empty(t, p.FindAllForPrefix("tests"), "expected no terms for longer prefix")
+	assert.Empty(t, p.FindAllForPrefix("x"), "expected no terms for different prefix")
+}
+
+func TestPrtrieWalkPrefixStopsEarly(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 1)
+	p.AddTerm("testing", 2)
+	p.AddTerm("tester", 5)
+	p.AddTerm("food", 3)
+	var seen []prtrie.Result
+	p.WalkPrefix("", func(r prtrie.Result) bool {
+		seen = append(seen, r)
+		return len(seen) < 2
+	})
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 1},
+		{Term: "tester", Freq: 5},
+	}, seen, "expected walk to stop once the callback returns false")
+}
+
 func TestResultString(t *testing.T) {
 	assert.Equal(t, "test:5", prtrie.Result{Term: "test", Freq: 5}.String())
 }
+
+func TestPrtrieAddPrefixTermKeepsSubtree(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("abcd", 1)
+	p.AddTerm("abcdef", 9)
+	p.AddTerm("ab", 2)
+	assert.Equal(t, uint64(3), p.GetTotalTermCount())
+	assert.Equal(t, strings.Join([]string{
+		"[0,9]",
+		"ab[2,9]",
+		"  cd[1,9]",
+		"  ef[9,9]",
+		"",
+	}, "\n"), p.String())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "abcdef", Freq: 9},
+		{Term: "ab", Freq: 2},
+		{Term: "abcd", Freq: 1},
+	}, p.TopKForPrefix("a", 3))
+}
+
+func TestPrtrieTiesAtKBoundary(t *testing.T) {
+	// the terms tied for the k-th place are spread over subtrees
+	// visited in either order, only the smallest terms are kept
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("zebra", 5)
+	p.AddTerm("zoo", 3)
+	p.AddTerm("zulu", 3)
+	p.AddTerm("apple", 3)
+	p.AddTerm("apex", 3)
+	p.AddTerm("mango", 3)
+	expected := []prtrie.Result{
+		{Term: "zebra", Freq: 5},
+		{Term: "apex", Freq: 3},
+		{Term: "apple", Freq: 3},
+	}
+	assert.Equal(t, expected, p.TopKForPrefix("", 3))
+	frozen, err := p.Freeze()
+	require.NoError(t, err)
+	assert.Equal(t, expected, frozen.TopKForPrefix("", 3))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "zebra", Freq: 5},
+		{Term: "zoo", Freq: 3},
+	}, p.TopKForPrefix("z", 2))
+}
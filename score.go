@@ -0,0 +1,74 @@
+package pruningradixtrie
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Scorer computes the score a term is ranked by from its normalized form
+// and its frequency, see WithScorer. Any function of the two works, since
+// the score of every term is stored along with the largest score below
+// each node to prune the search on.
+type Scorer func(term string, freq uint64) float64
+
+// LengthScorer returns a Scorer favoring short terms, dividing the frequency
+// by the number of bytes of the term raised to the power lengthPenalty.
+func LengthScorer(lengthPenalty float64) Scorer {
+	return func(term string, freq uint64) float64 {
+		return float64(freq) / math.Pow(float64(len(term)), lengthPenalty)
+	}
+}
+
+// scored reports whether terms are ranked by score rather than by frequency.
+func (p *pruningRadixTrie) scored() bool {
+	return p.cfg.scorer != nil || p.cfg.halfLife > 0
+}
+
+// score updates the score of the term at the end of a path from the root
+// after its count changed from old. The nodes of path must be owned by the
+// trie and their maxScore recomputed afterwards.
+// The decayed count of a term is scaled along with its count.
+func (p *pruningRadixTrie) score(path []*node, old uint64) {
+	n := path[len(path)-1]
+	switch {
+	case !p.scored():
+	case n.count == 0:
+		n.score = 0
+	case p.cfg.halfLife > 0:
+		if old > 0 {
+			n.score *= float64(n.count) / float64(old)
+		}
+	default:
+		var key strings.Builder
+		for _, n := range path {
+			key.WriteString(n.key)
+		}
+		n.score = p.cfg.scorer(key.String(), n.count)
+	}
+}
+
+// scoreAll computes the score of every term of a trie built without them,
+// the nodes must be owned by the trie.
+func (p *pruningRadixTrie) scoreAll() {
+	if !p.scored() {
+		return
+	}
+	if p.cfg.halfLife > 0 {
+		p.landmark = time.Now()
+	}
+	p.scoreSubtree("", p.trie)
+}
+
+func (p *pruningRadixTrie) scoreSubtree(path string, n *node) {
+	term := path + n.key
+	if n.count > 0 && p.cfg.halfLife > 0 {
+		n.score = float64(n.count)
+	} else if n.count > 0 {
+		n.score = p.cfg.scorer(term, n.count)
+	}
+	for _, child := range n.children {
+		p.scoreSubtree(term, child)
+	}
+	recomputeMaxCount(n)
+}
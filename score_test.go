@@ -0,0 +1,125 @@
+package pruningradixtrie_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// boosted scores terms ending in "!" ten times higher.
+func boosted(term string, freq uint64) float64 {
+	if term[len(term)-1] == '!' {
+		return 10 * float64(freq)
+	}
+	return float64(freq)
+}
+
+// sortedByScore ranks every term starting with prefix by score
+// the way TopKForPrefix should.
+func sortedByScore(trie prtrie.ReadOnlyTrie, prefix string) []prtrie.Result {
+	all := trie.FindAllForPrefix(prefix)
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Score != all[j].Score {
+			return all[i].Score > all[j].Score
+		}
+		if all[i].Freq != all[j].Freq {
+			return all[i].Freq > all[j].Freq
+		}
+		return all[i].Term < all[j].Term
+	})
+	return all
+}
+
+func TestScorerRanksByScore(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	p.AddTerm("test", 30)
+	p.AddTerm("testing", 20)
+	p.AddTerm("tested!", 5)
+	p.AddTerm("tester", 50)
+	// equal scores are ranked by frequency
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 50, Score: 50},
+		{Term: "tested!", Freq: 5, Score: 50},
+		{Term: "test", Freq: 30, Score: 30},
+	}, p.TopKForPrefix("te", 3))
+
+	// counts changing update the scores
+	p.DecrementTerm("tester", 45)
+	p.SetTerm("testing", 100)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 100, Score: 100},
+		{Term: "tested!", Freq: 5, Score: 50},
+	}, p.TopKForPrefix("test", 2))
+	p.RemoveTerm("testing")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tested!", Freq: 5, Score: 50},
+		{Term: "test", Freq: 30, Score: 30},
+	}, p.TopKForPrefix("test", 2))
+}
+
+func TestScorerMatchesFullSort(t *testing.T) {
+	scorer := prtrie.LengthScorer(1)
+	for seed := int64(0); seed < 10; seed++ {
+		results := randomTerms(200, seed)
+		incremental := prtrie.NewPruningRadixTrie(prtrie.WithScorer(scorer))
+		for _, r := range results {
+			incremental.AddTerm(r.Term, r.Freq)
+		}
+		built := prtrie.NewFromResults(results, prtrie.WithScorer(scorer))
+		for _, prefix := range []string{"", "a", "ab", "é", "\xe4\xb8"} {
+			expected := sortedByScore(incremental, prefix)
+			for _, k := range []int{1, 5, 20} {
+				want := expected[:min(k, len(expected))]
+				assert.Equal(t, want, incremental.TopKForPrefix(prefix, k), "seed %d prefix %q k %d", seed, prefix, k)
+				assert.Equal(t, want, built.TopKForPrefix(prefix, k), "seed %d prefix %q k %d", seed, prefix, k)
+			}
+		}
+	}
+}
+
+func TestLengthScorer(t *testing.T) {
+	assert.Equal(t, 5.0, prtrie.LengthScorer(1)("ab", 10))
+	assert.Equal(t, 10.0, prtrie.LengthScorer(0)("ab", 10))
+	assert.InDelta(t, 10/math.Sqrt(4), prtrie.LengthScorer(0.5)("abcd", 10), 1e-9)
+}
+
+func TestScorerReadFrom(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	p.AddTerm("go", 10)
+	p.AddTerm("gopher!", 2)
+	var buf bytes.Buffer
+	_, err := p.WriteTo(&buf)
+	require.NoError(t, err)
+
+	// scores are computed by the trie reading the data
+	read := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	_, err = read.ReadFrom(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, p.TopKForPrefix("g", 2), read.TopKForPrefix("g", 2))
+
+	_, err = p.Freeze()
+	assert.Error(t, err)
+}
+
+func TestScorerFuzzy(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithScorer(boosted))
+	p.AddTerm("tent", 30)
+	p.AddTerm("test!", 5)
+	p.AddTerm("text", 40)
+	assert.Equal(t, []prtrie.FuzzyResult{
+		{Result: prtrie.Result{Term: "test!", Freq: 5, Score: 50}, Distance: 1},
+		{Result: prtrie.Result{Term: "text", Freq: 40, Score: 40}, Distance: 1},
+	}, p.TopKForFuzzyPrefix("tesx", 2, 1))
+}
+
+func TestResultJSONOmitsZeroScore(t *testing.T) {
+	b, err := json.Marshal([]prtrie.Result{{Term: "test", Freq: 2}, {Term: "team", Freq: 1, Score: 1.5}})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"Term":"test","Freq":2},{"Term":"team","Freq":1,"Score":1.5}]`, string(b))
+}
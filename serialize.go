@@ -0,0 +1,305 @@
+package pruningradixtrie
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// The binary format written by WriteTo is
+//
+//	magic     "PRTRIE"
+//	version   byte
+//	flags     byte, flagForms if display forms follow each node
+//	termCount uvarint
+//	root node
+//	checksum  uint32 little endian, CRC-32 (IEEE) of everything before it
+//
+// where each node is written in pre-order as
+//
+//	key           uvarint length followed by the bytes
+//	count         uvarint
+//	maxChildCount uvarint
+//	forms         uvarint number of forms, each a length prefixed term and a uvarint count
+//	children      uvarint number of children followed by the children in trie order
+const (
+	formatMagic   = "PRTRIE"
+	formatVersion = 1
+	flagForms     = 1 << 0
+
+	// limits guarding against corrupted lengths before the checksum is verified
+	maxKeyLen   = 1 << 20
+	maxChildren = 1 << 20
+	maxForms    = 1 << 20
+	maxDepth    = 1 << 16
+)
+
+var (
+	ErrInvalidMagic       = errors.New("pruningradixtrie: not a pruning radix trie")
+	ErrUnsupportedVersion = errors.New("pruningradixtrie: unsupported format version")
+	ErrCorrupt            = errors.New("pruningradixtrie: corrupt trie data")
+)
+
+var _ io.WriterTo = &pruningRadixTrie{}
+var _ io.ReaderFrom = &pruningRadixTrie{}
+
+// WriteTo serializes the trie to w in a compact, versioned binary format
+// that ReadFrom loads back without having to rebuild it term by term.
+// Tries ReadFrom would reject, with keys or terms longer than 1 MiB or
+// deeper than 65536 nodes, fail with an error and may leave w partly written.
+func (p *pruningRadixTrie) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	crc := crc32.NewIEEE()
+	enc := &encoder{w: bufio.NewWriter(io.MultiWriter(cw, crc))}
+
+	var flags byte
+	if p.cfg.normalizer != nil {
+		flags |= flagForms
+	}
+	enc.bytes([]byte(formatMagic))
+	enc.bytes([]byte{formatVersion, flags})
+	enc.uvarint(p.termCount)
+	enc.node(p.trie, flags&flagForms != 0, 0)
+	if enc.err == nil {
+		enc.err = enc.w.Flush()
+	}
+	if enc.err != nil {
+		return cw.n, enc.err
+	}
+	_, err := cw.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32()))
+	return cw.n, err
+}
+
+// ReadFrom replaces the contents of the trie with a trie serialized by WriteTo.
+// Corrupted or truncated data is rejected with an error wrapping ErrCorrupt
+// and leaves the trie unchanged. A trie written with a normalizer can only be
+// read by a trie with a normalizer and vice versa.
+func (p *pruningRadixTrie) ReadFrom(r io.Reader) (int64, error) {
+	crc := crc32.NewIEEE()
+	dec := &decoder{r: bufio.NewReader(r), crc: crc}
+
+	// data too short to hold the magic is not a trie either
+	magic := dec.bytes(len(formatMagic))
+	if dec.err != nil || string(magic) != formatMagic {
+		return dec.n, ErrInvalidMagic
+	}
+	header := dec.bytes(2)
+	if dec.err != nil {
+		return dec.n, dec.err
+	}
+	if header[0] != formatVersion {
+		return dec.n, fmt.Errorf("%w %d, expected %d", ErrUnsupportedVersion, header[0], formatVersion)
+	}
+	if header[1]&^flagForms != 0 {
+		return dec.n, fmt.Errorf("%w: unknown flags %08b", ErrCorrupt, header[1])
+	}
+	forms := header[1]&flagForms != 0
+	if forms && p.cfg.normalizer == nil {
+		return dec.n, errors.New("pruningradixtrie: trie was written with a normalizer, read it into a trie with one")
+	} else if !forms && p.cfg.normalizer != nil {
+		return dec.n, errors.New("pruningradixtrie: trie was written without a normalizer, read it into a trie without one")
+	}
+	termCount := dec.uvarint()
+	root := &node{gen: p.gen}
+	terms := dec.node(root, forms, 0, p.gen)
+	sum := crc.Sum32()
+	checksum := dec.bytes(4)
+	if dec.err != nil {
+		return dec.n, dec.err
+	}
+	if stored := binary.LittleEndian.Uint32(checksum); stored != sum {
+		return dec.n, fmt.Errorf("%w: checksum %08x does not match the data, expected %08x", ErrCorrupt, stored, sum)
+	}
+	if terms != termCount {
+		return dec.n, fmt.Errorf("%w: found %d terms, expected %d", ErrCorrupt, terms, termCount)
+	}
+	p.trie = root
+	p.termCount = termCount
+	p.scoreAll()
+	return dec.n, nil
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (e *encoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *encoder) uvarint(v uint64) {
+	e.bytes(binary.AppendUvarint(e.buf[:0], v))
+}
+
+// limit fails the encoding if l exceeds what the decoder accepts.
+func (e *encoder) limit(what string, l, limit int) {
+	if e.err == nil && l > limit {
+		e.err = fmt.Errorf("pruningradixtrie: %s %d exceeds the limit of %d", what, l, limit)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.limit("length", len(s), maxKeyLen)
+	e.uvarint(uint64(len(s)))
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+func (e *encoder) node(n *node, forms bool, depth int) {
+	e.limit("depth", depth, maxDepth)
+	e.string(n.key)
+	e.uvarint(n.count)
+	e.uvarint(n.maxChildCount)
+	if forms {
+		e.limit("forms", len(n.forms), maxForms)
+		e.uvarint(uint64(len(n.forms)))
+		for _, f := range n.forms {
+			e.string(f.term)
+			e.uvarint(f.count)
+		}
+	}
+	e.limit("children", len(n.children), maxChildren)
+	e.uvarint(uint64(len(n.children)))
+	for _, child := range n.children {
+		e.node(child, forms, depth+1)
+	}
+}
+
+// decoder reads the binary format, every read after the first error is a no-op.
+type decoder struct {
+	r   *bufio.Reader
+	crc hash.Hash32
+	n   int64
+	err error
+	// buf is reused by every read, single bytes go through scratch
+	buf     []byte
+	scratch [1]byte
+}
+
+func (d *decoder) corrupt(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrCorrupt, fmt.Sprintf(format, args...))
+	}
+}
+
+// bytes reads l bytes, which are only valid until the next read.
+func (d *decoder) bytes(l int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if cap(d.buf) < l {
+		d.buf = make([]byte, l)
+	}
+	b := d.buf[:l]
+	read, err := io.ReadFull(d.r, b)
+	d.n += int64(read)
+	d.crc.Write(b[:read])
+	if err != nil {
+		d.corrupt("unexpected end of data at byte %d", d.n)
+		return nil
+	}
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		d.corrupt("unexpected end of data at byte %d", d.n)
+	} else if err != nil {
+		d.corrupt("invalid varint at byte %d", d.n)
+	}
+	return v
+}
+
+func (d *decoder) string(limit uint64) string {
+	l := d.uvarint()
+	if l > limit {
+		d.corrupt("length %d at byte %d exceeds %d", l, d.n, limit)
+	}
+	return string(d.bytes(int(l)))
+}
+
+// node decodes the node n and its subtree, returning the number of terms in it.
+func (d *decoder) node(n *node, forms bool, depth int, gen uint64) uint64 {
+	if depth > maxDepth {
+		d.corrupt("trie deeper than %d", maxDepth)
+	}
+	n.key = d.string(maxKeyLen)
+	if d.err == nil && depth > 0 && n.key == "" {
+		d.corrupt("empty key at byte %d", d.n)
+	}
+	n.count = d.uvarint()
+	n.maxChildCount = d.uvarint()
+	if d.err == nil && n.maxChildCount < n.count {
+		d.corrupt("max count %d below count %d at byte %d", n.maxChildCount, n.count, d.n)
+	}
+	if forms {
+		l := d.uvarint()
+		if l > maxForms {
+			d.corrupt("%d forms at byte %d exceed %d", l, d.n, maxForms)
+		}
+		var total uint64
+		for i := uint64(0); i < l && d.err == nil; i++ {
+			f := form{term: d.string(maxKeyLen), count: d.uvarint()}
+			total += f.count
+			n.forms = append(n.forms, f)
+		}
+		if d.err == nil && total != n.count {
+			d.corrupt("forms add up to %d instead of count %d at byte %d", total, n.count, d.n)
+		}
+	}
+	l := d.uvarint()
+	if l > maxChildren {
+		d.corrupt("%d children at byte %d exceed %d", l, d.n, maxChildren)
+	}
+	var terms uint64
+	if n.count > 0 {
+		terms++
+	}
+	for i := uint64(0); i < l && d.err == nil; i++ {
+		child := &node{gen: gen}
+		terms += d.node(child, forms, depth+1, gen)
+		if d.err == nil && child.maxChildCount > n.maxChildCount {
+			d.corrupt("max count %d above parent max count %d at byte %d", child.maxChildCount, n.maxChildCount, d.n)
+		}
+		n.children = append(n.children, child)
+	}
+	if d.err == nil && depth > 0 && n.count == 0 && len(n.children) < 2 {
+		d.corrupt("node without count and with %d children at byte %d", len(n.children), d.n)
+	}
+	return terms
+}
+
+// ReadByte reads single bytes for binary.ReadUvarint keeping track of them.
+func (d *decoder) ReadByte() (byte, error) {
+	c, err := d.r.ReadByte()
+	if err == nil {
+		d.n++
+		d.scratch[0] = c
+		d.crc.Write(d.scratch[:])
+	}
+	return c, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
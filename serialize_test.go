@@ -0,0 +1,123 @@
+package pruningradixtrie_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteToReadFromRoundTrip(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	for _, r := range unicodeTerms {
+		p.AddTerm(r.Term, r.Freq)
+	}
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+
+	b := &bytes.Buffer{}
+	written, err := p.WriteTo(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(b.Len()), written)
+
+	loaded := prtrie.NewPruningRadixTrie()
+	loaded.AddTerm("replaced", 1)
+	read, err := loaded.ReadFrom(bytes.NewReader(b.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, written, read)
+	assert.Equal(t, p.String(), loaded.String(), "expected the same nodes in the same order")
+	assert.Equal(t, p.GetTotalTermCount(), loaded.GetTotalTermCount())
+	assert.Equal(t, p.TopKForPrefix("", 100), loaded.TopKForPrefix("", 100))
+
+	loaded.AddTerm("testing", 100)
+	assert.Equal(t, []prtrie.Result{{Term: "testing", Freq: 105}}, loaded.TopKForPrefix("te", 1), "expected loaded trie to be updatable")
+}
+
+func TestWriteToReadFromEmpty(t *testing.T) {
+	b := &bytes.Buffer{}
+	_, err := prtrie.NewPruningRadixTrie().WriteTo(b)
+	require.NoError(t, err)
+	loaded := prtrie.NewPruningRadixTrie()
+	_, err = loaded.ReadFrom(b)
+	require.NoError(t, err)
+	assert.Equal(t, "[0,0]\n", loaded.String())
+}
+
+func TestWriteToReadFromKeyLimit(t *testing.T) {
+	const maxKeyLen = 1 << 20
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm(strings.Repeat("a", maxKeyLen), 1)
+	b := &bytes.Buffer{}
+	_, err := p.WriteTo(b)
+	require.NoError(t, err)
+	loaded := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	_, err = loaded.ReadFrom(b)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), loaded.GetTotalTermCount())
+
+	p.AddTerm(strings.Repeat("b", maxKeyLen+1), 1)
+	_, err = p.WriteTo(&bytes.Buffer{})
+	assert.Error(t, err, "expected a trie ReadFrom would reject not to be written")
+}
+
+func TestWriteToReadFromNormalized(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	p.AddTerm("Go", 2)
+	p.AddTerm("go", 1)
+	p.AddTerm("Gopher", 3)
+	b := &bytes.Buffer{}
+	_, err := p.WriteTo(b)
+	require.NoError(t, err)
+
+	_, err = prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err, "expected normalized trie to require a normalizer")
+
+	loaded := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	_, err = loaded.ReadFrom(bytes.NewReader(b.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "Go", Freq: 3},
+		{Term: "Gopher", Freq: 3},
+	}, loaded.TopKForPrefix("GO", 2))
+	loaded.AddTerm("go", 2)
+	assert.Equal(t, []prtrie.Result{{Term: "go", Freq: 5}}, loaded.TopKForPrefix("g", 1), "expected display form counts to be kept")
+}
+
+func TestReadFromRejectsInvalidData(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	b := &bytes.Buffer{}
+	_, err := p.WriteTo(b)
+	require.NoError(t, err)
+	data := b.Bytes()
+
+	_, err = prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader([]byte("not a trie")))
+	assert.ErrorIs(t, err, prtrie.ErrInvalidMagic)
+	_, err = prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader([]byte("a\t1\n")))
+	assert.ErrorIs(t, err, prtrie.ErrInvalidMagic, "expected data shorter than the magic not to be a trie")
+
+	version := append([]byte(nil), data...)
+	version[6] = 99
+	_, err = prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader(version))
+	assert.ErrorIs(t, err, prtrie.ErrUnsupportedVersion)
+
+	for i := range data {
+		loaded := prtrie.NewPruningRadixTrie()
+		loaded.AddTerm("untouched", 1)
+		_, err := loaded.ReadFrom(bytes.NewReader(data[:i]))
+		assert.Error(t, err, "expected data truncated at %d to be rejected", i)
+		assert.Equal(t, "[0,1]\nuntouched[1,1]\n", loaded.String(), "expected trie to be unchanged on error")
+	}
+	for i := len("PRTRIE") + 1; i < len(data); i++ {
+		corrupted := append([]byte(nil), data...)
+		corrupted[i] ^= 0x5a
+		_, err := prtrie.NewPruningRadixTrie().ReadFrom(bytes.NewReader(corrupted))
+		assert.ErrorIs(t, err, prtrie.ErrCorrupt, "expected data corrupted at %d to be reported as corrupt", i)
+	}
+}
@@ -0,0 +1,73 @@
+package pruningradixtrie
+
+import "sync/atomic"
+
+// generations hands out the ids tries use to tell the nodes they own,
+// and may change in place, from nodes shared with snapshots.
+// Concurrent tries also take their id from it.
+var generations atomic.Uint64
+
+func newGeneration() uint64 {
+	return generations.Add(1)
+}
+
+// Snapshot returns an immutable view of the trie as it is now.
+// The snapshot shares every node with the trie, which from then on copies
+// the nodes on the path of an update instead of changing them in place,
+// so taking a snapshot is O(1) and it keeps returning the same results
+// however the trie is updated afterwards.
+// A snapshot can be queried from any number of goroutines while the trie
+// is being updated.
+func (p *pruningRadixTrie) Snapshot() ReadOnlyTrie {
+	p.gen = newGeneration()
+	return &pruningRadixTrie{
+		trie:      p.trie,
+		termCount: p.termCount,
+		cfg:       p.cfg,
+		gen:       newGeneration(),
+		landmark:  p.landmark,
+	}
+}
+
+// newNode creates a node owned by the trie.
+func (p *pruningRadixTrie) newNode(term string, count uint64) *node {
+	n := newNode(term, count)
+	n.gen = p.gen
+	return n
+}
+
+// own returns n if the trie owns it, or a copy of it that the trie owns.
+func (p *pruningRadixTrie) own(n *node) *node {
+	if n.gen == p.gen {
+		return n
+	}
+	c := *n
+	c.gen = p.gen
+	c.children = append([]*node(nil), n.children...)
+	c.forms = append([]form(nil), n.forms...)
+	return &c
+}
+
+// ownChild makes the i-th child of the owned node n safe to change in place.
+func (p *pruningRadixTrie) ownChild(n *node, i int) *node {
+	child := p.own(n.children[i])
+	n.children[i] = child
+	return child
+}
+
+// ownPath makes every node of a path found from the root safe to change in place,
+// replacing them in path.
+func (p *pruningRadixTrie) ownPath(path []*node) []*node {
+	p.trie = p.own(p.trie)
+	path[0] = p.trie
+	for i := 1; i < len(path); i++ {
+		parent := path[i-1]
+		for j, child := range parent.children {
+			if child == path[i] {
+				path[i] = p.ownChild(parent, j)
+				break
+			}
+		}
+	}
+	return path
+}
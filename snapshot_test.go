@@ -0,0 +1,99 @@
+package pruningradixtrie_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotIsImmutable(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	p.AddTerm("food", 13)
+	s := p.Snapshot()
+	before := p.String()
+	expected := []prtrie.Result{
+		{Term: "test", Freq: 80},
+		{Term: "food", Freq: 13},
+		{Term: "tester", Freq: 10},
+		{Term: "testing", Freq: 5},
+	}
+	assert.Equal(t, expected, s.TopKForPrefix("", 4))
+
+	p.AddTerm("testing", 100)
+	p.AddTerm("team", 3)
+	p.AddTerm("t", 1)
+	p.RemoveTerm("test")
+	p.DecrementTerm("food", 9)
+	p.SetTerm("tester", 2)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 105},
+		{Term: "food", Freq: 4},
+		{Term: "team", Freq: 3},
+		{Term: "tester", Freq: 2},
+		{Term: "t", Freq: 1},
+	}, p.TopKForPrefix("", 5))
+
+	assert.Equal(t, expected, s.TopKForPrefix("", 4), "expected snapshot to keep its results")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "test", Freq: 80},
+		{Term: "tester", Freq: 10},
+		{Term: "testing", Freq: 5},
+	}, s.FindAllForPrefix("te"))
+	assert.Equal(t, uint64(4), s.GetTotalTermCount())
+	assert.Equal(t, uint64(5), p.GetTotalTermCount())
+	assert.Equal(t, before, s.(fmt.Stringer).String(), "expected snapshot nodes to be untouched")
+}
+
+func TestSnapshotOfSnapshots(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	var snapshots []prtrie.ReadOnlyTrie
+	for i := 1; i <= 5; i++ {
+		p.AddTerm("Term", 2)
+		p.AddTerm(fmt.Sprintf("term %d", i), uint64(i))
+		snapshots = append(snapshots, p.Snapshot())
+	}
+	p.RemoveTerm("TERM")
+	for i, s := range snapshots {
+		assert.Equal(t, uint64(i+2), s.GetTotalTermCount())
+		assert.Equal(t, []prtrie.Result{{Term: "Term", Freq: uint64(2 * (i + 1))}}, s.TopKForPrefix("term", 1))
+	}
+	assert.Equal(t, []prtrie.Result{{Term: "term 5", Freq: 5}}, p.TopKForPrefix("TERM", 1))
+}
+
+// TestSnapshotConcurrentReads queries snapshots without locking while the trie
+// they were taken from is updated, run with -race to detect shared writes.
+func TestSnapshotConcurrentReads(t *testing.T) {
+	p := prtrie.NewConcurrentPruningRadixTrie()
+	for i := 0; i < 100; i++ {
+		p.AddTerm(fmt.Sprintf("term %d", i), uint64(i+1))
+	}
+	s := p.Snapshot()
+	expected := s.TopKForPrefix("term", 10)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			p.AddTerm(fmt.Sprintf("term %d", i), 1000)
+			p.RemoveTerm(fmt.Sprintf("term %d", 99-i))
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				assert.Equal(t, expected, s.TopKForPrefix("term", 10))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(100), s.GetTotalTermCount())
+}
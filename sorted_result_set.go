@@ -24,7 +24,7 @@ func (s *sortedResults) PushResult(r Result) {
 	*s = append(*s, r)
 	sr := *s
 	sort.Slice(sr, func(i, j int) bool {
-		return sr[j].Freq < sr[i].Freq
+		return ranksBelow(sr[j], sr[i])
 	})
 }
 
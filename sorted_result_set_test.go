@@ -38,3 +38,19 @@ func TestSortedResultsAdd(t *testing.T) {
 		{Term: "bar", Freq: 1},
 	}, s.Results())
 }
+
+func TestSortedResultsTiesByTerm(t *testing.T) {
+	s := prtrie.NewSortedResults()
+	for _, term := range []string{"b", "d", "a", "c"} {
+		s.PushResult(prtrie.Result{Term: term, Freq: 1})
+	}
+	s.PushResult(prtrie.Result{Term: "e", Freq: 2})
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, s.PeekMinResult())
+	assert.Equal(t, prtrie.Result{Term: "d", Freq: 1}, s.PopResult())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "e", Freq: 2},
+		{Term: "a", Freq: 1},
+		{Term: "b", Freq: 1},
+		{Term: "c", Freq: 1},
+	}, s.Results())
+}
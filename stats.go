@@ -0,0 +1,62 @@
+package pruningradixtrie
+
+import "unsafe"
+
+// Stats describes the shape of a trie.
+type Stats struct {
+	// Terms is the number of terms, see GetTotalTermCount
+	Terms uint64
+	// Nodes is the number of nodes below the root
+	Nodes int
+	// SplitNodes is the number of nodes holding no term, created
+	// where the keys of several terms diverge
+	SplitNodes int
+	// MaxDepth is the largest number of nodes from the root down to a term
+	MaxDepth int
+	// AvgDepth is the average number of nodes from the root down to a term
+	AvgDepth float64
+	// KeyBytes is the total length of the node keys
+	KeyBytes int
+	// Branching maps a number of children to how many nodes have that many,
+	// the root included
+	Branching map[int]int
+	// MemoryBytes estimates the memory held by the nodes, their keys,
+	// children and display forms
+	MemoryBytes int
+}
+
+// Stats computes the statistics of the trie in a single traversal.
+// Nodes shared with snapshots are counted like the others.
+func (p *pruningRadixTrie) Stats() Stats {
+	s := Stats{
+		Terms:     p.termCount,
+		Branching: map[int]int{},
+	}
+	var depths int
+	stats(p.trie, 0, &s, &depths)
+	if p.termCount > 0 {
+		s.AvgDepth = float64(depths) / float64(p.termCount)
+	}
+	return s
+}
+
+// stats adds n and its subtree to s, and the depths of their terms to depths.
+func stats(n *node, depth int, s *Stats, depths *int) {
+	s.Branching[len(n.children)]++
+	s.KeyBytes += len(n.key)
+	s.MemoryBytes += int(unsafe.Sizeof(*n)) + len(n.key) +
+		cap(n.children)*int(unsafe.Sizeof(n)) + cap(n.forms)*int(unsafe.Sizeof(form{}))
+	for _, f := range n.forms {
+		s.MemoryBytes += len(f.term)
+	}
+	if n.count > 0 {
+		s.MaxDepth = max(s.MaxDepth, depth)
+		*depths += depth
+	} else if depth > 0 {
+		s.SplitNodes++
+	}
+	for _, child := range n.children {
+		s.Nodes++
+		stats(child, depth+1, s, depths)
+	}
+}
@@ -0,0 +1,54 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	empty := prtrie.NewPruningRadixTrie().Stats()
+	assert.Equal(t, map[int]int{0: 1}, empty.Branching)
+	assert.Zero(t, empty.Nodes)
+	assert.Zero(t, empty.AvgDepth)
+	assert.Positive(t, empty.MemoryBytes)
+
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 1)
+	p.AddTerm("testing", 1)
+	p.AddTerm("team", 1)
+	p.AddTerm("toast", 1)
+	// t -> e -> st -> ing, t -> e -> am, t -> oast
+	s := p.Stats()
+	assert.Equal(t, uint64(4), s.Terms)
+	assert.Equal(t, 6, s.Nodes)
+	assert.Equal(t, 2, s.SplitNodes)
+	assert.Equal(t, 4, s.MaxDepth)
+	assert.Equal(t, float64(3+4+3+2)/4, s.AvgDepth)
+	assert.Equal(t, len("t"+"e"+"st"+"ing"+"am"+"oast"), s.KeyBytes)
+	assert.Equal(t, map[int]int{0: 3, 1: 2, 2: 2}, s.Branching)
+	assert.Greater(t, s.MemoryBytes, empty.MemoryBytes+s.KeyBytes)
+
+	// display forms take memory too
+	n := prtrie.NewPruningRadixTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	n.AddTerm("test", 1)
+	n.AddTerm("testing", 1)
+	n.AddTerm("team", 1)
+	n.AddTerm("toast", 1)
+	assert.Greater(t, n.Stats().MemoryBytes, s.MemoryBytes)
+}
+
+func TestStatsRandom(t *testing.T) {
+	p := prtrie.NewFromResults(randomTerms(500, 12))
+	s := p.Stats()
+	assert.Equal(t, p.GetTotalTermCount(), s.Terms)
+	nodes := 0
+	for children, count := range s.Branching {
+		nodes += children * count
+	}
+	// every node but the root is the child of another one
+	assert.Equal(t, s.Nodes, nodes)
+	assert.Equal(t, s.Nodes, int(s.Terms)+s.SplitNodes)
+	assert.LessOrEqual(t, s.AvgDepth, float64(s.MaxDepth))
+}
@@ -0,0 +1,140 @@
+package pruningradixtrie
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports a malformed line of a terms file.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("pruningradixtrie: line %d: %s", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// LoadTerms reads terms in the format of the original C# PruningRadixTrie
+// term files, a term and its count separated by a tab on each line.
+// Lines may end in LF or CRLF and empty lines are skipped.
+func LoadTerms(r io.Reader) ([]Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxKeyLen)
+	var results []Result
+	line := 1
+	for ; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		term, count, ok := strings.Cut(text, "\t")
+		if !ok || strings.Contains(count, "\t") {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf(
+				"expected 2 tab separated values but got %d: %q", strings.Count(text, "\t")+1, text,
+			)}
+		}
+		freq, err := strconv.ParseUint(count, 10, 64)
+		if err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+		results = append(results, Result{Term: term, Freq: freq})
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return nil, &ParseError{Line: line, Err: err}
+	} else if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// LoadTermsGzip reads a gzip compressed terms file, see LoadTerms.
+func LoadTermsGzip(r io.Reader) ([]Result, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return LoadTerms(gr)
+}
+
+// LoadTermsZip reads the terms files in a zip archive, like the terms.zip
+// bundled with the original C# PruningRadixTrie, see LoadTerms.
+// The terms of all the files in the archive are returned in order.
+func LoadTermsZip(path string) ([]Result, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var results []Result
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		terms, err := LoadTerms(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		results = append(results, terms...)
+	}
+	return results, nil
+}
+
+// LoadTermsFile reads the terms file at path, a zip archive or a gzip
+// compressed file if its name ends in .zip or .gz, see LoadTerms.
+func LoadTermsFile(path string) ([]Result, error) {
+	if filepath.Ext(path) == ".zip" {
+		return LoadTermsZip(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".gz" {
+		return LoadTermsGzip(f)
+	}
+	return LoadTerms(f)
+}
+
+// WriteTerms writes terms in the format read by LoadTerms,
+// one term and its count separated by a tab per line.
+// Lines must fit in the 1 MiB LoadTerms reads, new line included.
+func WriteTerms(w io.Writer, results []Result) error {
+	bw := bufio.NewWriter(w)
+	for _, r := range results {
+		if strings.ContainsAny(r.Term, "\t\r\n") {
+			return fmt.Errorf("pruningradixtrie: term %q cannot contain tabs or line breaks", r.Term)
+		}
+		count := strconv.FormatUint(r.Freq, 10)
+		if l := len(r.Term) + len(count) + 2; l > maxKeyLen {
+			return fmt.Errorf("pruningradixtrie: line of %d bytes exceeds the limit of %d", l, maxKeyLen)
+		}
+		bw.WriteString(r.Term)
+		bw.WriteByte('\t')
+		bw.WriteString(count)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
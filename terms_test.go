@@ -0,0 +1,144 @@
+package pruningradixtrie_test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTerms(t *testing.T) {
+	for name, input := range map[string]string{
+		"LF":                "microsoft\t500\nmicro\t20\nmicrosoft office\t300\n",
+		"CRLF":              "microsoft\t500\r\nmicro\t20\r\nmicrosoft office\t300\r\n",
+		"no final new line": "microsoft\t500\nmicro\t20\r\n\nmicrosoft office\t300",
+	} {
+		results, err := prtrie.LoadTerms(bytes.NewBufferString(input))
+		require.NoError(t, err, name)
+		assert.Equal(t, []prtrie.Result{
+			{Term: "microsoft", Freq: 500},
+			{Term: "micro", Freq: 20},
+			{Term: "microsoft office", Freq: 300},
+		}, results, name)
+	}
+}
+
+func TestLoadTermsMalformed(t *testing.T) {
+	for input, line := range map[string]int{
+		"a\t1\nb\n":           2,
+		"a\t1\r\n\r\nb\t1\t2": 3,
+		"a\tmany":             1,
+		"a\t-1":               1,
+		"a\t1\nb\t1 ":         2,
+	} {
+		_, err := prtrie.LoadTerms(bytes.NewBufferString(input))
+		var parseErr *prtrie.ParseError
+		require.ErrorAs(t, err, &parseErr, "expected %q to be rejected", input)
+		assert.Equal(t, line, parseErr.Line, "expected line of %q to be reported", input)
+	}
+	_, err := prtrie.LoadTerms(bytes.NewBufferString("a\t18446744073709551616"))
+	assert.ErrorIs(t, err, strconv.ErrRange)
+
+	_, err = prtrie.LoadTerms(bytes.NewBufferString("a\t1\n" + strings.Repeat("a", 1<<20) + "\t1\n"))
+	var parseErr *prtrie.ParseError
+	require.ErrorAs(t, err, &parseErr, "expected a line over the key limit to be rejected")
+	assert.Equal(t, 2, parseErr.Line)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+func TestWriteTermsRoundTrip(t *testing.T) {
+	results := []prtrie.Result{
+		{Term: "microsoft", Freq: 500},
+		{Term: "中文", Freq: 18446744073709551615},
+		{Term: "microsoft office 365", Freq: 1},
+	}
+	b := &bytes.Buffer{}
+	require.NoError(t, prtrie.WriteTerms(b, results))
+	assert.Equal(t, "microsoft\t500\n中文\t18446744073709551615\nmicrosoft office 365\t1\n", b.String())
+	loaded, err := prtrie.LoadTerms(b)
+	require.NoError(t, err)
+	assert.Equal(t, results, loaded)
+
+	assert.Error(t, prtrie.WriteTerms(b, []prtrie.Result{{Term: "a\tb", Freq: 1}}))
+	assert.Error(t, prtrie.WriteTerms(b, []prtrie.Result{{Term: "a\nb", Freq: 1}}))
+
+	// the longest line LoadTerms reads, new line included
+	long := []prtrie.Result{{Term: strings.Repeat("a", 1<<20-3), Freq: 1}}
+	b.Reset()
+	require.NoError(t, prtrie.WriteTerms(b, long))
+	loaded, err = prtrie.LoadTerms(b)
+	require.NoError(t, err)
+	assert.Equal(t, long, loaded)
+	long[0].Term += "a"
+	assert.Error(t, prtrie.WriteTerms(b, long), "expected a line LoadTerms would reject not to be written")
+}
+
+func TestLoadTermsGzip(t *testing.T) {
+	b := &bytes.Buffer{}
+	gw := gzip.NewWriter(b)
+	_, err := gw.Write([]byte("microsoft\t500\r\nmicro\t20\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	results, err := prtrie.LoadTermsGzip(b)
+	require.NoError(t, err)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft", Freq: 500},
+		{Term: "micro", Freq: 20},
+	}, results)
+
+	_, err = prtrie.LoadTermsGzip(bytes.NewBufferString("microsoft\t500"))
+	assert.Error(t, err)
+}
+
+func TestLoadTermsZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terms.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("terms.txt")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("microsoft\t500\r\nmicro\t20\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	results, err := prtrie.LoadTermsZip(path)
+	require.NoError(t, err)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft", Freq: 500},
+		{Term: "micro", Freq: 20},
+	}, results)
+
+	_, err = prtrie.LoadTermsZip(filepath.Join(t.TempDir(), "missing.zip"))
+	assert.Error(t, err)
+}
+
+func TestLoadTermsFile(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "terms.txt")
+	require.NoError(t, os.WriteFile(plain, []byte("microsoft\t500\n"), 0o644))
+	b := &bytes.Buffer{}
+	gw := gzip.NewWriter(b)
+	_, err := gw.Write([]byte("microsoft\t500\n"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	gz := filepath.Join(dir, "terms.txt.gz")
+	require.NoError(t, os.WriteFile(gz, b.Bytes(), 0o644))
+
+	for _, path := range []string{plain, gz} {
+		results, err := prtrie.LoadTermsFile(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, []prtrie.Result{{Term: "microsoft", Freq: 500}}, results, path)
+	}
+	_, err = prtrie.LoadTermsFile(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
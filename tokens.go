@@ -0,0 +1,89 @@
+package pruningradixtrie
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TokenMode tells how the tokens of a TopKForTokens query match the words
+// of a term.
+type TokenMode int
+
+const (
+	// TokensInOrder matches terms where the tokens are prefixes of
+	// consecutive words, in the order of the query.
+	TokensInOrder TokenMode = iota
+	// TokensAnyOrder matches terms where every token is the prefix
+	// of a different word, in any order.
+	TokensAnyOrder
+)
+
+// TopKForTokens splits query on white space and returns the top k terms
+// whose words start with the tokens as told by mode, "micro off" completing
+// "microsoft office". Terms are ranked like TopKForPrefix.
+// The terms having a word starting with the first token are searched with
+// the usual pruning, those not matching the other tokens being left out of
+// the results.
+func (w *WordTrie) TopKForTokens(query string, k int, mode TokenMode) []Result {
+	tokens := strings.Fields(w.p.normalize(query))
+	if k <= 0 || len(tokens) == 0 {
+		return nil
+	}
+	results := newTopKResults(tokens[0], k, Result{})
+	w.topKForWordPrefix(tokens[0], k, &matchingResults{
+		ResultSet: results,
+		match: func(r Result) bool {
+			words := strings.FieldsFunc(w.p.normalize(r.Term), unicode.IsSpace)
+			if mode == TokensAnyOrder {
+				return matchAnyOrder(tokens, words, make([]bool, len(words)))
+			}
+			return matchInOrder(tokens, words)
+		},
+	})
+	return results.Results()
+}
+
+// matchInOrder reports whether the tokens are prefixes of consecutive words.
+func matchInOrder(tokens, words []string) bool {
+	for start := 0; start+len(tokens) <= len(words); start++ {
+		i := 0
+		for i < len(tokens) && strings.HasPrefix(words[start+i], tokens[i]) {
+			i++
+		}
+		if i == len(tokens) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyOrder reports whether every token is the prefix of a different word
+// among the words not used yet.
+func matchAnyOrder(tokens, words []string, used []bool) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	for i, word := range words {
+		if used[i] || !strings.HasPrefix(word, tokens[0]) {
+			continue
+		}
+		used[i] = true
+		if matchAnyOrder(tokens[1:], words, used) {
+			return true
+		}
+		used[i] = false
+	}
+	return false
+}
+
+// matchingResults drops the results match rejects.
+type matchingResults struct {
+	ResultSet
+	match func(Result) bool
+}
+
+func (m *matchingResults) PushResult(r Result) {
+	if m.match(r) {
+		m.ResultSet.PushResult(r)
+	}
+}
@@ -0,0 +1,57 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+func tokenTrie() *prtrie.WordTrie {
+	w := prtrie.NewWordTrie()
+	w.AddTerm("microsoft office", 10)
+	w.AddTerm("microsoft office 365", 8)
+	w.AddTerm("office microsoft", 6)
+	w.AddTerm("micro four thirds office", 4)
+	w.AddTerm("microwave oven", 2)
+	return w
+}
+
+func TestTopKForTokensInOrder(t *testing.T) {
+	w := tokenTrie()
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office", Freq: 10},
+		{Term: "microsoft office 365", Freq: 8},
+	}, w.TopKForTokens("micro off", 10, prtrie.TokensInOrder))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office", Freq: 10},
+		{Term: "microsoft office 365", Freq: 8},
+		{Term: "office microsoft", Freq: 6},
+		{Term: "micro four thirds office", Freq: 4},
+		{Term: "microwave oven", Freq: 2},
+	}, w.TopKForTokens("  micro  ", 10, prtrie.TokensInOrder))
+	// consecutive words need not start the term
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office 365", Freq: 8},
+	}, w.TopKForTokens("off 3", 10, prtrie.TokensInOrder))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office", Freq: 10},
+	}, w.TopKForTokens("micro off", 1, prtrie.TokensInOrder))
+	assert.Nil(t, w.TopKForTokens(" ", 10, prtrie.TokensInOrder))
+	assert.Nil(t, w.TopKForTokens("micro", 0, prtrie.TokensInOrder))
+}
+
+func TestTopKForTokensAnyOrder(t *testing.T) {
+	w := tokenTrie()
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office", Freq: 10},
+		{Term: "microsoft office 365", Freq: 8},
+		{Term: "office microsoft", Freq: 6},
+		{Term: "micro four thirds office", Freq: 4},
+	}, w.TopKForTokens("off micro", 10, prtrie.TokensAnyOrder))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "micro four thirds office", Freq: 4},
+	}, w.TopKForTokens("th off fo", 10, prtrie.TokensAnyOrder))
+	// every token needs a word of its own
+	assert.Empty(t, w.TopKForTokens("off off", 10, prtrie.TokensAnyOrder))
+}
@@ -0,0 +1,130 @@
+package pruningradixtrie_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+// unicodeTerms share leading bytes inside multi-byte runes so that a byte
+// oriented split would cut them in half.
+var unicodeTerms = []prtrie.Result{
+	// CJK, 中 (e4 b8 ad) 丰 (e4 b8 b0) 丸 (e4 b8 b8) share two leading bytes
+	{Term: "中文", Freq: 50},
+	{Term: "中国", Freq: 40},
+	{Term: "丰富", Freq: 30},
+	{Term: "丸子", Freq: 20},
+	{Term: "日本語", Freq: 10},
+	// emoji, including a ZWJ sequence and a flag
+	{Term: "😀", Freq: 9},
+	{Term: "😁 grin", Freq: 8},
+	{Term: "👨‍👩‍👧", Freq: 7},
+	{Term: "👨‍👩‍👦", Freq: 6},
+	{Term: "🇫🇷", Freq: 5},
+	{Term: "🇫🇮", Freq: 4},
+	// combining marks next to their precomposed forms
+	{Term: "cafe\u0301", Freq: 3},
+	{Term: "caf\u00e9", Freq: 2},
+	{Term: "caf\u00e9 au lait", Freq: 1},
+}
+
+func newUnicodeTrie(opts ...prtrie.Option) prtrie.PruningRadixTrie {
+	p := prtrie.NewPruningRadixTrie(opts...)
+	for _, r := range unicodeTerms {
+		p.AddTerm(r.Term, r.Freq)
+	}
+	return p
+}
+
+func TestUnicodeKeysAreValidUTF8(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	for _, r := range unicodeTerms {
+		p.AddTerm(r.Term, r.Freq)
+	}
+	assert.Equal(t, uint64(len(unicodeTerms)), p.GetTotalTermCount())
+	for _, line := range strings.Split(p.String(), "\n") {
+		assert.True(t, utf8.ValidString(line), "expected node key to be valid UTF-8: %q", line)
+	}
+}
+
+func TestByteKeysSplitRunes(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie(prtrie.WithByteKeys())
+	p.AddTerm("中文", 1)
+	p.AddTerm("丰富", 1)
+	assert.False(t, utf8.ValidString(p.String()), "expected byte keys to split inside a rune")
+	assert.ElementsMatch(t, []prtrie.Result{
+		{Term: "中文", Freq: 1},
+		{Term: "丰富", Freq: 1},
+	}, p.FindAllForPrefix(""))
+}
+
+func TestUnicodeTopKForPrefix(t *testing.T) {
+	p := newUnicodeTrie()
+	assert.Equal(t, unicodeTerms, p.TopKForPrefix("", len(unicodeTerms)))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "中文", Freq: 50},
+		{Term: "中国", Freq: 40},
+	}, p.TopKForPrefix("中", 5))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "中国", Freq: 40},
+	}, p.TopKForPrefix("中国", 5))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "👨‍👩‍👧", Freq: 7},
+		{Term: "👨‍👩‍👦", Freq: 6},
+	}, p.TopKForPrefix("👨‍👩‍", 5))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "🇫🇷", Freq: 5},
+		{Term: "🇫🇮", Freq: 4},
+	}, p.TopKForPrefix("🇫", 5))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "cafe\u0301", Freq: 3},
+	}, p.TopKForPrefix("cafe", 5), "expected decomposed form to only match its own bytes")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "caf\u00e9", Freq: 2},
+		{Term: "caf\u00e9 au lait", Freq: 1},
+	}, p.TopKForPrefix("caf\u00e9", 5))
+}
+
+func TestUnicodePartialRunePrefix(t *testing.T) {
+	p := newUnicodeTrie()
+	// 中, 丰 and 丸 all start with e4 b8
+	assert.Equal(t, []prtrie.Result{
+		{Term: "中文", Freq: 50},
+		{Term: "中国", Freq: 40},
+		{Term: "丰富", Freq: 30},
+		{Term: "丸子", Freq: 20},
+	}, p.TopKForPrefix("\xe4\xb8", 5), "expected partial rune to match every rune starting with its bytes")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "中文", Freq: 50},
+		{Term: "中国", Freq: 40},
+		{Term: "丰富", Freq: 30},
+	}, p.TopKForPrefix("\xe4", 3))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "丰富", Freq: 30},
+	}, p.TopKForPrefix("\xe4\xb8\xb0", 5))
+	assert.Empty(t, p.TopKForPrefix("\xe4\xb9", 5))
+
+	all := p.FindAllForPrefix("\xe4\xb8")
+	sort.Slice(all, func(i, j int) bool { return all[i].Freq > all[j].Freq })
+	assert.Equal(t, unicodeTerms[:4], all)
+}
+
+func TestUnicodeRemoveTerm(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	for _, r := range unicodeTerms {
+		p.AddTerm(r.Term, r.Freq)
+	}
+	for _, r := range unicodeTerms {
+		count, ok := p.RemoveTerm(r.Term)
+		assert.True(t, ok, "expected %q to be removed", r.Term)
+		assert.Equal(t, r.Freq, count)
+		for _, line := range strings.Split(p.String(), "\n") {
+			assert.True(t, utf8.ValidString(line), "expected node key to be valid UTF-8: %q", line)
+		}
+	}
+	assert.Empty(t, p.FindAllForPrefix(""))
+}
@@ -0,0 +1,151 @@
+package pruningradixtrie
+
+import "strings"
+
+// RemoveTerm implements PruningRadixTrie.
+// Deletes term from the trie, returning its former frequency
+// and whether it existed.
+func (p *pruningRadixTrie) RemoveTerm(term string) (uint64, bool) {
+	path := p.findPath(p.normalize(term))
+	if path == nil {
+		return 0, false
+	}
+	path = p.ownPath(path)
+	n := path[len(path)-1]
+	count := n.count
+	n.count = 0
+	n.forms = nil
+	n.score = 0
+	p.termCount--
+	p.compact(path)
+	return count, true
+}
+
+// DecrementTerm implements PruningRadixTrie.
+// Lowers the frequency of term by by, returning the new frequency
+// and whether the term existed. A term decremented to zero is removed.
+// When the trie normalizes keys the count is taken from the display form term first.
+func (p *pruningRadixTrie) DecrementTerm(term string, by uint64) (uint64, bool) {
+	path := p.findPath(p.normalize(term))
+	if path == nil {
+		return 0, false
+	}
+	path = p.ownPath(path)
+	n := path[len(path)-1]
+	if by >= n.count {
+		p.RemoveTerm(term)
+		return 0, true
+	}
+	n.count -= by
+	if p.cfg.normalizer != nil {
+		n.removeForms(term, by)
+	}
+	p.score(path, n.count+by)
+	p.compact(path)
+	return n.count, true
+}
+
+// SetTerm implements PruningRadixTrie.
+// Sets the frequency of term to count, adding the term if needed,
+// and returns its former frequency and whether it existed.
+// Setting a frequency of zero removes the term.
+func (p *pruningRadixTrie) SetTerm(term string, count uint64) (uint64, bool) {
+	if count == 0 {
+		return p.RemoveTerm(term)
+	}
+	path := p.findPath(p.normalize(term))
+	if path == nil {
+		p.AddTerm(term, count)
+		return 0, false
+	}
+	path = p.ownPath(path)
+	n := path[len(path)-1]
+	old := n.count
+	if p.cfg.normalizer != nil && count > old {
+		n.addForm(term, count-old)
+	} else if p.cfg.normalizer != nil {
+		n.removeForms(term, old-count)
+	}
+	n.count = count
+	p.score(path, old)
+	p.compact(path)
+	return old, true
+}
+
+// findPath returns the nodes from the root down to the node holding the
+// normalized term, or nil if term was never added or has been removed.
+func (p *pruningRadixTrie) findPath(term string) []*node {
+	if term == "" {
+		return nil
+	}
+	path := []*node{p.trie}
+	cur := p.trie
+	for term != "" {
+		var next *node
+		for _, child := range cur.children {
+			if strings.HasPrefix(term, child.key) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		term = term[len(next.key):]
+		path = append(path, next)
+		cur = next
+	}
+	if cur.count == 0 {
+		return nil
+	}
+	return path
+}
+
+// compact restores the trie invariants along path after a count was lowered.
+// Walking from the bottom up, count-0 leaves are dropped, count-0 nodes left
+// with a single child are merged with it, and maxChildCount is recomputed
+// from scratch since, unlike on insertion, it may have to go down.
+// The nodes of path must be owned by the trie.
+func (p *pruningRadixTrie) compact(path []*node) {
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		if i > 0 && n.count == 0 {
+			switch len(n.children) {
+			case 0:
+				removeChild(path[i-1], n)
+				continue
+			case 1:
+				child := p.ownChild(n, 0)
+				n.key += child.key
+				n.count = child.count
+				n.forms = child.forms
+				n.score = child.score
+				n.children = child.children
+			}
+		}
+		recomputeMaxCount(n)
+	}
+}
+
+// removeChild removes child from the children of n keeping their order.
+func removeChild(n, child *node) {
+	for i, c := range n.children {
+		if c == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// recomputeMaxCount re-sorts the children of n and sets its maxChildCount
+// and maxScore to the largest count and score in its subtree, assuming
+// the children are up to date.
+func recomputeMaxCount(n *node) {
+	updateChildren(n)
+	n.maxChildCount = n.count
+	n.maxScore = n.score
+	for _, child := range n.children {
+		n.maxChildCount = max(n.maxChildCount, child.maxChildCount)
+		n.maxScore = max(n.maxScore, child.maxScore)
+	}
+}
@@ -0,0 +1,194 @@
+package pruningradixtrie_test
+
+import (
+	"strings"
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveTermMissing(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	count, ok := p.RemoveTerm("test")
+	assert.False(t, ok)
+	assert.Zero(t, count)
+
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	for _, term := range []string{"", "t", "test", "tests", "testingly", "x"} {
+		count, ok := p.RemoveTerm(term)
+		assert.False(t, ok, "expected %q not to be removed", term)
+		assert.Zero(t, count)
+	}
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+}
+
+func TestRemoveTermOnlyTerm(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 7)
+	count, ok := p.RemoveTerm("test")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), count)
+	assert.Equal(t, uint64(0), p.GetTotalTermCount())
+	assert.Empty(t, p.TopKForPrefix("", 1))
+	assert.Equal(t, "[0,0]\n", p.String())
+
+	count, ok = p.RemoveTerm("test")
+	assert.False(t, ok, "expected term to be removed only once")
+	assert.Zero(t, count)
+}
+
+func TestRemoveTermMergesSplitNode(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	count, ok := p.RemoveTerm("tester")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), count)
+	assert.Equal(t, uint64(1), p.GetTotalTermCount())
+	assert.Equal(t, strings.Join([]string{
+		"[0,5]",
+		"testing[5,5]",
+		"",
+	}, "\n"), p.String())
+	assert.Equal(t, []prtrie.Result{{Term: "testing", Freq: 5}}, p.TopKForPrefix("test", 2))
+}
+
+func TestRemoveTermInternalNode(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	p.AddTerm("food", 13)
+	count, ok := p.RemoveTerm("test")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(80), count)
+	assert.Equal(t, uint64(3), p.GetTotalTermCount())
+	assert.Equal(t, strings.Join([]string{
+		"[0,13]",
+		"food[13,13]",
+		"test[0,10]",
+		"    er[10,10]",
+		"    ing[5,5]",
+		"",
+	}, "\n"), p.String(), "expected the max counts to go down and children to be reordered")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "food", Freq: 13},
+		{Term: "tester", Freq: 10},
+	}, p.TopKForPrefix("", 2))
+
+	p.RemoveTerm("testing")
+	assert.Equal(t, strings.Join([]string{
+		"[0,13]",
+		"food[13,13]",
+		"tester[10,10]",
+		"",
+	}, "\n"), p.String(), "expected the split node to be merged with its remaining child")
+}
+
+func TestRemoveTermThenAddAgain(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	p.RemoveTerm("testing")
+	p.AddTerm("testing", 1)
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 10},
+		{Term: "testing", Freq: 1},
+	}, p.TopKForPrefix("test", 2))
+}
+
+func TestDecrementTerm(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	p.AddTerm("test", 80)
+	p.AddTerm("testing", 5)
+	p.AddTerm("tester", 10)
+	p.AddTerm("food", 13)
+
+	count, ok := p.DecrementTerm("test", 78)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, strings.Join([]string{
+		"[0,13]",
+		"food[13,13]",
+		"test[2,10]",
+		"    er[10,10]",
+		"    ing[5,5]",
+		"",
+	}, "\n"), p.String())
+	assert.Equal(t, []prtrie.Result{
+		{Term: "food", Freq: 13},
+		{Term: "tester", Freq: 10},
+		{Term: "testing", Freq: 5},
+	}, p.TopKForPrefix("", 3))
+
+	count, ok = p.DecrementTerm("tester", 10)
+	assert.True(t, ok)
+	assert.Zero(t, count)
+	assert.Equal(t, uint64(3), p.GetTotalTermCount(), "expected term decremented to zero to be removed")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "testing", Freq: 5},
+		{Term: "test", Freq: 2},
+	}, p.TopKForPrefix("test", 3))
+
+	count, ok = p.DecrementTerm("tester", 1)
+	assert.False(t, ok)
+	assert.Zero(t, count)
+}
+
+func TestSetTerm(t *testing.T) {
+	p := prtrie.NewPruningRadixTrie()
+	old, ok := p.SetTerm("testing", 5)
+	assert.False(t, ok, "expected new term to be added")
+	assert.Zero(t, old)
+	p.SetTerm("tester", 10)
+	assert.Equal(t, uint64(2), p.GetTotalTermCount())
+
+	old, ok = p.SetTerm("tester", 3)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), old)
+	assert.Equal(t, strings.Join([]string{
+		"[0,5]",
+		"test[0,5]",
+		"    ing[5,5]",
+		"    er[3,3]",
+		"",
+	}, "\n"), p.String())
+
+	old, ok = p.SetTerm("tester", 30)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), old)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "tester", Freq: 30},
+		{Term: "testing", Freq: 5},
+	}, p.TopKForPrefix("t", 2))
+
+	old, ok = p.SetTerm("testing", 0)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), old)
+	assert.Equal(t, uint64(1), p.GetTotalTermCount(), "expected setting zero to remove the term")
+	assert.Equal(t, "[0,30]\ntester[30,30]\n", p.String())
+}
+
+func TestSetTermWithoutNormalizerKeepsNoForms(t *testing.T) {
+	set := prtrie.NewPruningRadixTrie()
+	set.AddTerm("test", 5)
+	set.AddTerm("tester", 2)
+	set.SetTerm("test", 9)
+	set.SetTerm("tester", 1)
+	set.DecrementTerm("test", 2)
+
+	added := prtrie.NewPruningRadixTrie()
+	added.AddTerm("test", 7)
+	added.AddTerm("tester", 1)
+	assert.Equal(t, added.Stats(), set.Stats())
+
+	setFrozen, err := set.Freeze()
+	require.NoError(t, err)
+	addedFrozen, err := added.Freeze()
+	require.NoError(t, err)
+	assert.Equal(t, addedFrozen.Bytes(), setFrozen.Bytes())
+}
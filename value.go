@@ -0,0 +1,138 @@
+package pruningradixtrie
+
+// ValueResult is a Result along with the value attached to its term.
+type ValueResult[V any] struct {
+	Result
+	Value V
+}
+
+// ValueTrie is a trie attaching a value of type V to every term,
+// such as the URL or ID of the entity a suggestion stands for.
+//
+// The trie itself is not generic: ValueTrie wraps a PruningRadixTrie and
+// keeps the values in a map by normalized term, updated along with the
+// trie so a value is dropped with its term. This costs a copy of every
+// normalized term as a map key on top of the trie, and a normalization and
+// map lookup for every result returned. Only the methods below know about
+// values: there is no concurrent or snapshot variant of a ValueTrie, and
+// values are not written by WriteTo or Freeze, which deal with terms and
+// counts only.
+type ValueTrie[V any] struct {
+	p      *pruningRadixTrie
+	values map[string]V
+	// merge updates the value of a term added again, nil to replace it
+	merge func(old, added V) V
+}
+
+// NewValueTrie returns an empty ValueTrie. Adding a term again replaces its value.
+func NewValueTrie[V any](opts ...Option) *ValueTrie[V] {
+	return NewValueTrieWithMerge[V](nil, opts...)
+}
+
+// NewValueTrieWithMerge returns an empty ValueTrie updating the value of
+// a term added again with merge, called with its current value and the one added.
+func NewValueTrieWithMerge[V any](merge func(old, added V) V, opts ...Option) *ValueTrie[V] {
+	return &ValueTrie[V]{p: NewPruningRadixTrie(opts...), values: map[string]V{}, merge: merge}
+}
+
+func (t *ValueTrie[V]) GetTotalTermCount() uint64 {
+	return t.p.GetTotalTermCount()
+}
+
+// AddTerm adds count occurrences of term like PruningRadixTrie.AddTerm
+// and attaches value to it, see NewValueTrie and NewValueTrieWithMerge.
+func (t *ValueTrie[V]) AddTerm(term string, count uint64, value V) {
+	key := t.p.normalize(term)
+	if key == "" || count == 0 {
+		return
+	}
+	t.p.AddTerm(term, count)
+	t.addValue(key, value)
+}
+
+// addValue attaches value to key, merging it with the current one.
+func (t *ValueTrie[V]) addValue(key string, value V) {
+	if old, ok := t.values[key]; ok && t.merge != nil {
+		value = t.merge(old, value)
+	}
+	t.values[key] = value
+}
+
+// SetTerm sets the frequency of term to count like PruningRadixTrie.SetTerm
+// and replaces its value. Setting a frequency of zero removes the term and its value.
+func (t *ValueTrie[V]) SetTerm(term string, count uint64, value V) (uint64, bool) {
+	key := t.p.normalize(term)
+	if key == "" {
+		return 0, false
+	}
+	old, ok := t.p.SetTerm(term, count)
+	if count == 0 {
+		delete(t.values, key)
+	} else {
+		t.values[key] = value
+	}
+	return old, ok
+}
+
+// Merge adds every term of other along with its value to the trie,
+// merging the values of the terms both hold like AddTerm does.
+// See pruningRadixTrie.Merge.
+func (t *ValueTrie[V]) Merge(other *ValueTrie[V]) {
+	t.p.Merge(other.p)
+	for key, value := range other.values {
+		t.addValue(key, value)
+	}
+}
+
+// Value returns the value attached to term and whether the term exists.
+func (t *ValueTrie[V]) Value(term string) (V, bool) {
+	value, ok := t.values[t.p.normalize(term)]
+	return value, ok
+}
+
+// RemoveTerm deletes term and its value, see PruningRadixTrie.RemoveTerm.
+func (t *ValueTrie[V]) RemoveTerm(term string) (uint64, bool) {
+	delete(t.values, t.p.normalize(term))
+	return t.p.RemoveTerm(term)
+}
+
+// DecrementTerm lowers the frequency of term keeping its value,
+// see PruningRadixTrie.DecrementTerm.
+func (t *ValueTrie[V]) DecrementTerm(term string, by uint64) (uint64, bool) {
+	count, ok := t.p.DecrementTerm(term, by)
+	if ok && count == 0 {
+		delete(t.values, t.p.normalize(term))
+	}
+	return count, ok
+}
+
+// TopKForPrefix returns the top k terms starting with prefix
+// along with their values, see PruningRadixTrie.TopKForPrefix.
+func (t *ValueTrie[V]) TopKForPrefix(prefix string, k int) []ValueResult[V] {
+	return t.withValues(t.p.TopKForPrefix(prefix, k))
+}
+
+// TopKForPrefixAfter returns the page of results ranking below after
+// along with their values, see PruningRadixTrie.TopKForPrefixAfter.
+func (t *ValueTrie[V]) TopKForPrefixAfter(prefix string, k int, after Result) []ValueResult[V] {
+	return t.withValues(t.p.TopKForPrefixAfter(prefix, k, after))
+}
+
+// FindAllForPrefix returns every term starting with prefix
+// along with their values, see PruningRadixTrie.FindAllForPrefix.
+func (t *ValueTrie[V]) FindAllForPrefix(prefix string) []ValueResult[V] {
+	return t.withValues(t.p.FindAllForPrefix(prefix))
+}
+
+// withValues attaches the value of every result, looked up by key
+// rather than by walking the trie again.
+func (t *ValueTrie[V]) withValues(results []Result) []ValueResult[V] {
+	if results == nil {
+		return nil
+	}
+	values := make([]ValueResult[V], 0, len(results))
+	for _, r := range results {
+		values = append(values, ValueResult[V]{Result: r, Value: t.values[t.p.normalize(r.Term)]})
+	}
+	return values
+}
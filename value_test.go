@@ -0,0 +1,119 @@
+package pruningradixtrie_test
+
+import (
+	"testing"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+)
+
+type entity struct {
+	URL string
+	ID  int
+}
+
+func TestValueTrieTopK(t *testing.T) {
+	v := prtrie.NewValueTrie[entity]()
+	v.AddTerm("golang", 10, entity{URL: "https://go.dev", ID: 1})
+	v.AddTerm("gopher", 5, entity{URL: "https://go.dev/blog/gopher", ID: 2})
+	v.AddTerm("go", 7, entity{URL: "https://go.dev/doc", ID: 3})
+	assert.Equal(t, uint64(3), v.GetTotalTermCount())
+	assert.Equal(t, []prtrie.ValueResult[entity]{
+		{Result: prtrie.Result{Term: "golang", Freq: 10}, Value: entity{URL: "https://go.dev", ID: 1}},
+		{Result: prtrie.Result{Term: "go", Freq: 7}, Value: entity{URL: "https://go.dev/doc", ID: 3}},
+	}, v.TopKForPrefix("go", 2))
+	assert.Equal(t, []prtrie.ValueResult[entity]{
+		{Result: prtrie.Result{Term: "gopher", Freq: 5}, Value: entity{URL: "https://go.dev/blog/gopher", ID: 2}},
+	}, v.TopKForPrefixAfter("go", 2, prtrie.Result{Term: "go", Freq: 7}))
+	assert.Len(t, v.FindAllForPrefix("gop"), 1)
+	assert.Empty(t, v.TopKForPrefix("rust", 2))
+}
+
+func TestValueTrieReplacesValues(t *testing.T) {
+	v := prtrie.NewValueTrie[string]()
+	v.AddTerm("test", 1, "first")
+	v.AddTerm("test", 2, "second")
+	value, ok := v.Value("test")
+	assert.True(t, ok)
+	assert.Equal(t, "second", value)
+	assert.Equal(t, uint64(3), v.TopKForPrefix("te", 1)[0].Freq)
+}
+
+func TestValueTrieMergesValues(t *testing.T) {
+	v := prtrie.NewValueTrieWithMerge(func(old, added []string) []string {
+		return append(old, added...)
+	})
+	v.AddTerm("test", 1, []string{"a"})
+	v.AddTerm("test", 1, []string{"b"})
+	v.AddTerm("testing", 1, []string{"c"})
+	value, _ := v.Value("test")
+	assert.Equal(t, []string{"a", "b"}, value)
+	value, _ = v.Value("testing")
+	assert.Equal(t, []string{"c"}, value)
+}
+
+func TestValueTrieRemoveTerm(t *testing.T) {
+	v := prtrie.NewValueTrie[int]()
+	v.AddTerm("test", 1, 1)
+	v.AddTerm("testing", 1, 2)
+	v.AddTerm("team", 1, 3)
+
+	// removing test merges its node with the one of testing
+	_, ok := v.RemoveTerm("test")
+	assert.True(t, ok)
+	_, ok = v.Value("test")
+	assert.False(t, ok)
+	value, ok := v.Value("testing")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	// a term added again after removal does not get its old value back
+	v.AddTerm("test", 1, 4)
+	value, _ = v.Value("test")
+	assert.Equal(t, 4, value)
+
+	count, ok := v.DecrementTerm("testing", 0)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+	value, _ = v.Value("testing")
+	assert.Equal(t, 2, value)
+}
+
+func TestValueTrieNormalizer(t *testing.T) {
+	v := prtrie.NewValueTrie[int](prtrie.WithNormalizer(prtrie.CaseFold))
+	v.AddTerm("Go", 2, 1)
+	v.AddTerm("go", 1, 2)
+	assert.Equal(t, []prtrie.ValueResult[int]{
+		{Result: prtrie.Result{Term: "Go", Freq: 3}, Value: 2},
+	}, v.TopKForPrefix("G", 1))
+}
+
+func TestValueTrieSetTerm(t *testing.T) {
+	v := prtrie.NewValueTrieWithMerge(func(old, added int) int {
+		return old + added
+	})
+	old, ok := v.SetTerm("test", 3, 1)
+	assert.False(t, ok)
+	assert.Zero(t, old)
+	v.AddTerm("test", 1, 2)
+	value, _ := v.Value("test")
+	assert.Equal(t, 3, value, "expected added values to be merged")
+
+	// setting a term replaces its value rather than merging it
+	old, ok = v.SetTerm("test", 2, 10)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), old)
+	assert.Equal(t, []prtrie.ValueResult[int]{
+		{Result: prtrie.Result{Term: "test", Freq: 2}, Value: 10},
+	}, v.TopKForPrefix("te", 1))
+
+	v.SetTerm("test", 0, 10)
+	_, ok = v.Value("test")
+	assert.False(t, ok, "expected a term set to zero to lose its value")
+
+	v.AddTerm("test", 2, 5)
+	v.DecrementTerm("test", 2)
+	v.AddTerm("test", 1, 6)
+	value, _ = v.Value("test")
+	assert.Equal(t, 6, value, "expected a term decremented to zero to lose its value")
+}
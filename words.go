@@ -0,0 +1,180 @@
+package pruningradixtrie
+
+import (
+	"time"
+	"unicode"
+)
+
+// wordSeparator ends the word suffix in the keys of the word index,
+// keeping apart the entries of different terms sharing that suffix.
+const wordSeparator = "\x00"
+
+// WordTrie completes prefixes matching the start of any word of a term,
+// so "off" completes "microsoft office 365", words being separated by
+// Unicode white space.
+// Terms are held by a trie like any other, and a second one indexes every
+// word but the first as the end of the term from that word on, followed by
+// the whole term so that entries of different terms never merge. Queries
+// search both with the usual pruning, dropping terms reached through
+// several words. Options apply to the terms, while the index always
+// takes its counts and scores from them.
+type WordTrie struct {
+	p     *pruningRadixTrie
+	words *pruningRadixTrie
+}
+
+// NewWordTrie returns an empty WordTrie.
+func NewWordTrie(opts ...Option) *WordTrie {
+	w := &WordTrie{
+		p:     NewPruningRadixTrie(opts...),
+		words: NewPruningRadixTrie(),
+	}
+	w.words.cfg.byteKeys = w.p.cfg.byteKeys
+	return w
+}
+
+func (w *WordTrie) GetTotalTermCount() uint64 {
+	return w.p.GetTotalTermCount()
+}
+
+// AddTerm adds term, see PruningRadixTrie.AddTerm.
+func (w *WordTrie) AddTerm(term string, count uint64) {
+	landmark := w.p.landmark
+	w.p.AddTerm(term, count)
+	w.rescaled(landmark)
+	w.sync(w.p.normalize(term))
+}
+
+// AddTermAt adds term at time at, see pruningRadixTrie.AddTermAt.
+func (w *WordTrie) AddTermAt(term string, count uint64, at time.Time) {
+	landmark := w.p.landmark
+	w.p.AddTermAt(term, count, at)
+	w.rescaled(landmark)
+	w.sync(w.p.normalize(term))
+}
+
+// Rescale moves the landmark of a decaying trie, see pruningRadixTrie.Rescale.
+func (w *WordTrie) Rescale(at time.Time) {
+	landmark := w.p.landmark
+	w.p.Rescale(at)
+	w.rescaled(landmark)
+}
+
+// DecayedScore converts the Score of a result, see pruningRadixTrie.DecayedScore.
+func (w *WordTrie) DecayedScore(score float64, at time.Time) float64 {
+	return w.p.DecayedScore(score, at)
+}
+
+// rescaled rescales the word index like the terms were if their landmark
+// moved away from landmark, so the index keeps the same scores as the terms.
+func (w *WordTrie) rescaled(landmark time.Time) {
+	if landmark.IsZero() || landmark.Equal(w.p.landmark) {
+		return
+	}
+	w.words.trie = w.words.own(w.words.trie)
+	w.words.rescale(w.words.trie, w.p.decay(w.p.landmark.Sub(landmark)))
+}
+
+// RemoveTerm removes term, see PruningRadixTrie.RemoveTerm.
+func (w *WordTrie) RemoveTerm(term string) (uint64, bool) {
+	count, ok := w.p.RemoveTerm(term)
+	w.sync(w.p.normalize(term))
+	return count, ok
+}
+
+// DecrementTerm lowers the frequency of term, see PruningRadixTrie.DecrementTerm.
+func (w *WordTrie) DecrementTerm(term string, by uint64) (uint64, bool) {
+	count, ok := w.p.DecrementTerm(term, by)
+	w.sync(w.p.normalize(term))
+	return count, ok
+}
+
+// SetTerm sets the frequency of term, see PruningRadixTrie.SetTerm.
+func (w *WordTrie) SetTerm(term string, count uint64) (uint64, bool) {
+	old, ok := w.p.SetTerm(term, count)
+	w.sync(w.p.normalize(term))
+	return old, ok
+}
+
+// TopKForPrefix returns the top k terms starting with prefix,
+// see PruningRadixTrie.TopKForPrefix.
+func (w *WordTrie) TopKForPrefix(prefix string, k int) []Result {
+	return w.p.TopKForPrefix(prefix, k)
+}
+
+// TopKForWordPrefix returns the top k terms having a word starting with
+// prefix, ranked like TopKForPrefix. A prefix spanning several words
+// matches them in order, "office 3" completing "microsoft office 365".
+func (w *WordTrie) TopKForWordPrefix(prefix string, k int) []Result {
+	if k <= 0 {
+		return nil
+	}
+	prefix = w.p.normalize(prefix)
+	results := newTopKResults(prefix, k, Result{})
+	w.topKForWordPrefix(prefix, k, results)
+	return results.Results()
+}
+
+// topKForWordPrefix pushes the top k terms having a word starting with
+// the normalized prefix into results.
+func (w *WordTrie) topKForWordPrefix(prefix string, k int, results ResultSet) {
+	unique := &uniqueResults{ResultSet: results, seen: map[string]bool{}}
+	// the index does not hold its terms in key order
+	topKForPrefix(prefix, "", w.p.trie, k, unique, false)
+	topKForPrefix(prefix, "", w.words.trie, k, unique, false)
+}
+
+// sync updates the index entries of the term with the normalized key
+// after its count changed in the terms.
+func (w *WordTrie) sync(key string) {
+	var term *node
+	if path := w.p.findPath(key); path != nil {
+		term = path[len(path)-1]
+	}
+	for _, start := range wordStarts(key) {
+		entry := key[start:] + wordSeparator + key
+		if term == nil {
+			w.words.RemoveTerm(entry)
+			continue
+		}
+		w.words.SetTerm(entry, term.count)
+		path := w.words.ownPath(w.words.findPath(entry))
+		n := path[len(path)-1]
+		n.forms = []form{{term: term.term(key), count: term.count}}
+		n.score = term.score
+		for i := len(path) - 1; i >= 0; i-- {
+			recomputeMaxCount(path[i])
+		}
+	}
+}
+
+// wordStarts returns the byte offsets of the words of term but the first.
+func wordStarts(term string) []int {
+	var starts []int
+	space := false
+	for i, r := range term {
+		if unicode.IsSpace(r) {
+			space = true
+		} else if space {
+			starts = append(starts, i)
+			space = false
+		}
+	}
+	return starts
+}
+
+// uniqueResults drops the results for terms already pushed. A term pushed
+// again through another word has the same rank, so it would not make it
+// into the results either if it was pushed out of them.
+type uniqueResults struct {
+	ResultSet
+	seen map[string]bool
+}
+
+func (u *uniqueResults) PushResult(r Result) {
+	if u.seen[r.Term] {
+		return
+	}
+	u.seen[r.Term] = true
+	u.ResultSet.PushResult(r)
+}
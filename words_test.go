@@ -0,0 +1,122 @@
+package pruningradixtrie_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	prtrie "github.com/elielamora/pruningradixtrie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordTrie(t *testing.T) {
+	w := prtrie.NewWordTrie()
+	w.AddTerm("microsoft office 365", 10)
+	w.AddTerm("office depot", 7)
+	w.AddTerm("the office office", 3)
+	w.AddTerm("officer", 1)
+	assert.Equal(t, uint64(4), w.GetTotalTermCount())
+
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office 365", Freq: 10},
+		{Term: "office depot", Freq: 7},
+		{Term: "the office office", Freq: 3},
+		{Term: "officer", Freq: 1},
+	}, w.TopKForWordPrefix("off", 10))
+	// the term matching through two words is returned once
+	assert.Equal(t, []prtrie.Result{
+		{Term: "the office office", Freq: 3},
+	}, w.TopKForWordPrefix("office o", 10))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office 365", Freq: 10},
+	}, w.TopKForWordPrefix("office 3", 10))
+	assert.Equal(t, []prtrie.Result{
+		{Term: "office depot", Freq: 7},
+		{Term: "officer", Freq: 1},
+	}, w.TopKForPrefix("off", 10))
+	assert.Empty(t, w.TopKForWordPrefix("ffice", 10))
+	assert.Nil(t, w.TopKForWordPrefix("off", 0))
+
+	// the index follows the counts of the terms
+	w.SetTerm("the office office", 20)
+	w.DecrementTerm("microsoft office 365", 9)
+	w.RemoveTerm("office depot")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "the office office", Freq: 20},
+		{Term: "microsoft office 365", Freq: 1},
+		{Term: "officer", Freq: 1},
+	}, w.TopKForWordPrefix("off", 10))
+	w.RemoveTerm("the office office")
+	assert.Equal(t, []prtrie.Result{
+		{Term: "microsoft office 365", Freq: 1},
+	}, w.TopKForWordPrefix("office ", 10))
+}
+
+func TestWordTrieMatchesScan(t *testing.T) {
+	results := randomTerms(300, 11)
+	w := prtrie.NewWordTrie()
+	for _, r := range results {
+		w.AddTerm(r.Term, r.Freq)
+	}
+	all := w.TopKForPrefix("", len(results))
+	for _, prefix := range []string{"a", "ab", "b", "é", "中", "a b"} {
+		var expected []prtrie.Result
+		for _, r := range all {
+			if hasWordPrefix(r.Term, prefix) {
+				expected = append(expected, r)
+			}
+		}
+		sort.SliceStable(expected, func(i, j int) bool {
+			return expected[i].Freq > expected[j].Freq
+		})
+		for _, k := range []int{1, 5, 50} {
+			assert.Equal(t, expected[:min(k, len(expected))], w.TopKForWordPrefix(prefix, k), "prefix %q k %d", prefix, k)
+		}
+	}
+}
+
+// hasWordPrefix reports whether a word of term starts with prefix.
+func hasWordPrefix(term, prefix string) bool {
+	for i, r := range term {
+		start := i == 0 || unicode.IsSpace(rune(term[i-1]))
+		if start && !unicode.IsSpace(r) && strings.HasPrefix(term[i:], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWordTrieNormalizer(t *testing.T) {
+	w := prtrie.NewWordTrie(prtrie.WithNormalizer(prtrie.CaseFold))
+	w.AddTerm("Microsoft Office", 2)
+	assert.Equal(t, []prtrie.Result{
+		{Term: "Microsoft Office", Freq: 2},
+	}, w.TopKForWordPrefix("OFF", 1))
+}
+
+func TestWordTrieRescale(t *testing.T) {
+	w := prtrie.NewWordTrie(prtrie.WithDecay(time.Hour))
+	w.AddTermAt("microsoft office", 1, epoch)
+	w.AddTermAt("office depot", 1, epoch.Add(time.Hour))
+	// far enough past the landmark for the terms to be rescaled
+	at := epoch.Add(300 * time.Hour)
+	w.AddTermAt("home office", 1, at)
+
+	results := w.TopKForWordPrefix("office", 3)
+	require.Len(t, results, 3)
+	assert.Equal(t, "home office", results[0].Term)
+	assert.Equal(t, "microsoft office", results[2].Term, "expected the oldest term to rank last")
+	for _, r := range results {
+		assert.Equal(t, w.TopKForPrefix(r.Term, 1)[0].Score, r.Score, "expected the index to have the score of %q", r.Term)
+	}
+	assert.InDelta(t, 1, w.DecayedScore(results[0].Score, at), 1e-9)
+
+	w.Rescale(epoch.Add(400 * time.Hour))
+	results = w.TopKForWordPrefix("office", 3)
+	for _, r := range results {
+		assert.Equal(t, w.TopKForPrefix(r.Term, 1)[0].Score, r.Score, "expected the index to have the score of %q after Rescale", r.Term)
+	}
+}